@@ -0,0 +1,218 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/applications"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	"github.com/microsoftgraph/msgraph-sdk-go/serviceprincipals"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+type (
+	applicationCredential struct {
+		Type        string
+		KeyID       string
+		DisplayName string
+		Hint        string
+		Usage       string
+		StartTime   *time.Time
+		EndTime     *time.Time
+	}
+)
+
+func (auditor *AzureAuditor) auditApplications(ctx context.Context, logger *zap.SugaredLogger, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchApplications(ctx, logger)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.Applications.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.Applications.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.Applications.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal Application credentials", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.application)
+	}
+}
+
+func (auditor *AzureAuditor) fetchApplications(ctx context.Context, logger *zap.SugaredLogger) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+	client := auditor.azure.msGraph.ServiceClient()
+
+	// app registrations
+	applicationResult, err := client.Applications().Get(ctx, &applications.ApplicationsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &applications.ApplicationsRequestBuilderGetQueryParameters{
+			Select: []string{"id", "appId", "displayName", "passwordCredentials", "keyCredentials"},
+			Expand: []string{"owners"},
+		},
+	})
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		for _, application := range applicationResult.GetValue() {
+			obj := map[string]interface{}{
+				"application.type":        "application",
+				"application.objectid":    stringPtrToStringLower(application.GetId()),
+				"application.appid":       stringPtrToStringLower(application.GetAppId()),
+				"application.displayname": to.String(application.GetDisplayName()),
+				"application.owners":      msGraphDirectoryObjectListToStringList(application.GetOwners()),
+			}
+
+			credentialList := msGraphApplicationCredentialList(application.GetPasswordCredentials(), application.GetKeyCredentials())
+			list = append(list, newApplicationCredentialAzureObjectList("applications", obj, credentialList)...)
+		}
+
+		if applicationResult.GetOdataNextLink() == nil {
+			break
+		}
+
+		applicationResult, err = client.Applications().WithUrl(*applicationResult.GetOdataNextLink()).Get(ctx, nil)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	// service principals (enterprise applications)
+	servicePrincipalResult, err := client.ServicePrincipals().Get(ctx, &serviceprincipals.ServicePrincipalsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &serviceprincipals.ServicePrincipalsRequestBuilderGetQueryParameters{
+			Select: []string{"id", "appId", "displayName", "servicePrincipalType", "passwordCredentials", "keyCredentials"},
+			Expand: []string{"owners"},
+		},
+	})
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		for _, servicePrincipal := range servicePrincipalResult.GetValue() {
+			obj := map[string]interface{}{
+				"application.type":                 "serviceprincipal",
+				"application.objectid":             stringPtrToStringLower(servicePrincipal.GetId()),
+				"application.appid":                stringPtrToStringLower(servicePrincipal.GetAppId()),
+				"application.displayname":          to.String(servicePrincipal.GetDisplayName()),
+				"application.serviceprincipaltype": to.String(servicePrincipal.GetServicePrincipalType()),
+				"application.owners":               msGraphDirectoryObjectListToStringList(servicePrincipal.GetOwners()),
+			}
+
+			credentialList := msGraphApplicationCredentialList(servicePrincipal.GetPasswordCredentials(), servicePrincipal.GetKeyCredentials())
+			list = append(list, newApplicationCredentialAzureObjectList("serviceprincipals", obj, credentialList)...)
+		}
+
+		if servicePrincipalResult.GetOdataNextLink() == nil {
+			break
+		}
+
+		servicePrincipalResult, err = client.ServicePrincipals().WithUrl(*servicePrincipalResult.GetOdataNextLink()).Get(ctx, nil)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	return
+}
+
+// newApplicationCredentialAzureObjectList creates one AzureObject per credential (secret or certificate)
+func newApplicationCredentialAzureObjectList(objectType string, application map[string]interface{}, credentialList []applicationCredential) (list []*validator.AzureObject) {
+	for _, credential := range credentialList {
+		obj := map[string]interface{}{
+			"resource.id": fmt.Sprintf("/%s/%s/credentials/%s", objectType, application["application.objectid"], credential.KeyID),
+
+			"credential.type":        credential.Type,
+			"credential.keyid":       credential.KeyID,
+			"credential.displayname": credential.DisplayName,
+			"credential.hint":        credential.Hint,
+			"credential.usage":       credential.Usage,
+		}
+
+		for key, val := range application {
+			obj[key] = val
+		}
+
+		if credential.StartTime != nil {
+			obj["credential.startdatetime"] = credential.StartTime.Format(time.RFC3339)
+			obj["credential.age"] = time.Since(*credential.StartTime)
+		}
+
+		if credential.EndTime != nil {
+			obj["credential.enddatetime"] = credential.EndTime.Format(time.RFC3339)
+			obj["credential.expiry"] = time.Until(*credential.EndTime)
+		}
+
+		if credential.StartTime != nil && credential.EndTime != nil {
+			obj["credential.lifetime"] = credential.EndTime.Sub(*credential.StartTime)
+		}
+
+		list = append(list, validator.NewAzureObject(obj))
+	}
+
+	return
+}
+
+func msGraphApplicationCredentialList(passwordCredentials []models.PasswordCredentialable, keyCredentials []models.KeyCredentialable) (list []applicationCredential) {
+	for _, credential := range passwordCredentials {
+		keyId := ""
+		if credential.GetKeyId() != nil {
+			keyId = credential.GetKeyId().String()
+		}
+
+		list = append(list, applicationCredential{
+			Type:        "password",
+			KeyID:       keyId,
+			DisplayName: to.String(credential.GetDisplayName()),
+			Hint:        to.String(credential.GetHint()),
+			StartTime:   credential.GetStartDateTime(),
+			EndTime:     credential.GetEndDateTime(),
+		})
+	}
+
+	for _, credential := range keyCredentials {
+		keyId := ""
+		if credential.GetKeyId() != nil {
+			keyId = credential.GetKeyId().String()
+		}
+
+		list = append(list, applicationCredential{
+			Type:        "certificate",
+			KeyID:       keyId,
+			DisplayName: to.String(credential.GetDisplayName()),
+			Usage:       strings.ToLower(to.String(credential.GetUsage())),
+			StartTime:   credential.GetStartDateTime(),
+			EndTime:     credential.GetEndDateTime(),
+		})
+	}
+
+	return
+}
+
+// msGraphDirectoryObjectListToStringList returns the userPrincipalName (users) or displayName (eg. service principals) of the objects
+func msGraphDirectoryObjectListToStringList(val []models.DirectoryObjectable) (list []string) {
+	list = []string{}
+	for _, row := range val {
+		switch v := row.(type) {
+		case models.Userable:
+			list = append(list, to.String(v.GetUserPrincipalName()))
+		case models.ServicePrincipalable:
+			list = append(list, to.String(v.GetDisplayName()))
+		default:
+			list = append(list, to.String(row.GetId()))
+		}
+	}
+	return
+}
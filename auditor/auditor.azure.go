@@ -4,11 +4,15 @@ import (
 	"context"
 	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization"
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 
 	"github.com/webdevops/go-common/utils/to"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
 )
 
 func (auditor *AzureAuditor) getSubscriptionList(ctx context.Context) (list map[string]*armsubscriptions.Subscription) {
@@ -44,7 +48,11 @@ func (auditor *AzureAuditor) getResourceList(ctx context.Context, subscription *
 	if err != nil {
 		auditor.Logger.Panic(err)
 	}
-	pager := client.NewListPager(nil)
+
+	// provisioningState is only returned if expanded
+	pager := client.NewListPager(&armresources.ClientListOptions{
+		Expand: to.StringPtr("provisioningState"),
+	})
 
 	for pager.More() {
 		result, err := pager.NextPage(ctx)
@@ -66,13 +74,82 @@ func (auditor *AzureAuditor) getResourceList(ctx context.Context, subscription *
 	return
 }
 
+// getKeyvaultList returns all keyvaults (including properties) of the subscription, shared by all keyvault reports
+func (auditor *AzureAuditor) getKeyvaultList(ctx context.Context, subscription *armsubscriptions.Subscription) (list map[string]*armkeyvault.Vault) {
+	auditor.locks.keyvaults.Lock()
+	defer auditor.locks.keyvaults.Unlock()
+
+	list = map[string]*armkeyvault.Vault{}
+
+	cacheKey := "keyvaults:" + *subscription.SubscriptionID
+	if val, ok := auditor.cache.Get(cacheKey); ok {
+		// fetched from cache
+		list = val.(map[string]*armkeyvault.Vault)
+		return
+	}
+
+	return auditor.fetchKeyvaultList(ctx, subscription)
+}
+
+// refreshKeyvaultList fetches all keyvaults (including properties) of the subscription bypassing the cache and updates the cache
+func (auditor *AzureAuditor) refreshKeyvaultList(ctx context.Context, subscription *armsubscriptions.Subscription) (list map[string]*armkeyvault.Vault) {
+	auditor.locks.keyvaults.Lock()
+	defer auditor.locks.keyvaults.Unlock()
+
+	return auditor.fetchKeyvaultList(ctx, subscription)
+}
+
+// fetchKeyvaultList fetches all keyvaults (including properties) of the subscription and updates the cache, keyvaults lock must be held
+func (auditor *AzureAuditor) fetchKeyvaultList(ctx context.Context, subscription *armsubscriptions.Subscription) (list map[string]*armkeyvault.Vault) {
+	list = map[string]*armkeyvault.Vault{}
+
+	cacheKey := "keyvaults:" + *subscription.SubscriptionID
+
+	client, err := armkeyvault.NewVaultsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		auditor.Logger.Panic(err)
+	}
+
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			auditor.Logger.Panic(err)
+		}
+
+		for _, item := range result.Value {
+			resourceInfo, _ := azureCommon.ParseResourceId(to.String(item.ID))
+
+			// list only returns generic resource information, properties are only available via get
+			keyvaultResource, err := client.Get(ctx, resourceInfo.ResourceGroup, resourceInfo.ResourceName, nil)
+			if err != nil {
+				auditor.Logger.Panic(err)
+			}
+
+			resourceID := strings.ToLower(to.String(keyvaultResource.ID))
+			list[resourceID] = &keyvaultResource.Vault
+		}
+	}
+
+	auditor.Logger.Infof("found %v Azure KeyVaults for Subscription %v (%v) (cache update)", len(list), to.String(subscription.DisplayName), to.String(subscription.SubscriptionID))
+
+	// save to cache (overwrites the cached list on refresh)
+	auditor.cache.Set(cacheKey, list, auditor.cacheExpiry)
+
+	return
+}
+
 func (auditor *AzureAuditor) getRoleDefinitionList(ctx context.Context, subscription *armsubscriptions.Subscription) (list map[string]*armauthorization.RoleDefinition) {
+	return auditor.getRoleDefinitionListByScope(ctx, *subscription.ID)
+}
+
+func (auditor *AzureAuditor) getRoleDefinitionListByScope(ctx context.Context, scope string) (list map[string]*armauthorization.RoleDefinition) {
 	auditor.locks.resources.Lock()
 	defer auditor.locks.resources.Unlock()
 
 	list = map[string]*armauthorization.RoleDefinition{}
 
-	cacheKey := "roledefinitions:" + *subscription.SubscriptionID
+	cacheKey := "roledefinitions:" + scope
 	if val, ok := auditor.cache.Get(cacheKey); ok {
 		// fetched from cache
 		list = val.(map[string]*armauthorization.RoleDefinition)
@@ -84,7 +161,7 @@ func (auditor *AzureAuditor) getRoleDefinitionList(ctx context.Context, subscrip
 		auditor.Logger.Panic(err)
 	}
 
-	pager := client.NewListPager(*subscription.ID, nil)
+	pager := client.NewListPager(scope, nil)
 	for pager.More() {
 		result, err := pager.NextPage(ctx)
 		if err != nil {
@@ -97,7 +174,54 @@ func (auditor *AzureAuditor) getRoleDefinitionList(ctx context.Context, subscrip
 		}
 	}
 
-	auditor.Logger.Infof("found %v Azure RoleDefinitions for Subscription %v (%v) (cache update)", len(list), to.String(subscription.DisplayName), to.String(subscription.SubscriptionID))
+	auditor.Logger.Infof("found %v Azure RoleDefinitions for scope %v (cache update)", len(list), scope)
+
+	// save to cache
+	_ = auditor.cache.Add(cacheKey, list, auditor.cacheExpiry)
+
+	return
+}
+
+func (auditor *AzureAuditor) getManagementGroupList(ctx context.Context) (list map[string]*armmanagementgroups.ManagementGroup) {
+	auditor.locks.managementGroups.Lock()
+	defer auditor.locks.managementGroups.Unlock()
+
+	list = map[string]*armmanagementgroups.ManagementGroup{}
+
+	cacheKey := "managementgroups"
+	if val, ok := auditor.cache.Get(cacheKey); ok {
+		// fetched from cache
+		list = val.(map[string]*armmanagementgroups.ManagementGroup)
+		return
+	}
+
+	client, err := armmanagementgroups.NewClient(auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		auditor.Logger.Panic(err)
+	}
+
+	expandPath := armmanagementgroups.ManagementGroupExpandTypePath
+
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			auditor.Logger.Panic(err)
+		}
+
+		for _, item := range result.Value {
+			// list only returns basic information, path and parent are only available via get
+			managementGroup, err := client.Get(ctx, to.String(item.Name), &armmanagementgroups.ClientGetOptions{Expand: &expandPath})
+			if err != nil {
+				auditor.Logger.Panic(err)
+			}
+
+			resourceID := strings.ToLower(to.String(managementGroup.ID))
+			list[resourceID] = &managementGroup.ManagementGroup
+		}
+	}
+
+	auditor.Logger.Infof("found %v Azure ManagementGroups (cache update)", len(list))
 
 	// save to cache
 	_ = auditor.cache.Add(cacheKey, list, auditor.cacheExpiry)
@@ -0,0 +1,123 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/microsoftgraph/msgraph-sdk-go/users"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditClassicAdministrators(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchClassicAdministrators(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.ClassicAdministrators.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.ClassicAdministrators.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.ClassicAdministrators.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal ClassicAdministrators", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.classicAdministrator)
+	}
+}
+
+func (auditor *AzureAuditor) fetchClassicAdministrators(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	client, err := armauthorization.NewClassicAdministratorsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, classicAdministrator := range result.Value {
+			if classicAdministrator.Properties == nil {
+				continue
+			}
+
+			emailAddress := strings.ToLower(to.String(classicAdministrator.Properties.EmailAddress))
+
+			// role contains multiple roles for service administrator (eg. "ServiceAdministrator;AccountAdministrator")
+			roleList := []string{}
+			for _, role := range strings.Split(to.String(classicAdministrator.Properties.Role), ";") {
+				if role = strings.TrimSpace(role); role != "" {
+					roleList = append(roleList, role)
+				}
+			}
+
+			obj := map[string]interface{}{
+				"resource.id":        stringPtrToStringLower(classicAdministrator.ID),
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"principal.objectid": auditor.lookupMsGraphUserObjectIdByEmail(ctx, emailAddress),
+
+				"classicadministrator.name":         to.String(classicAdministrator.Name),
+				"classicadministrator.emailaddress": emailAddress,
+				"classicadministrator.role":         to.String(classicAdministrator.Properties.Role),
+				"classicadministrator.roles":        roleList,
+			}
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// lookupMsGraphUserObjectIdByEmail returns the objectid of the user (member or guest) with the email address
+// classic administrators are only referenced by their email address
+func (auditor *AzureAuditor) lookupMsGraphUserObjectIdByEmail(ctx context.Context, emailAddress string) (objectId string) {
+	if emailAddress == "" {
+		return
+	}
+
+	cacheKey := "msgraph:user:email:" + emailAddress
+	if val, ok := auditor.cache.Get(cacheKey); ok {
+		// fetched from cache
+		return val.(string)
+	}
+
+	emailFilter := strings.ReplaceAll(emailAddress, "'", "''")
+	filter := fmt.Sprintf("userPrincipalName eq '%[1]s' or mail eq '%[1]s'", emailFilter)
+
+	result, err := auditor.azure.msGraph.ServiceClient().Users().Get(ctx, &users.UsersRequestBuilderGetRequestConfiguration{
+		QueryParameters: &users.UsersRequestBuilderGetQueryParameters{
+			Filter: &filter,
+			Select: []string{"id"},
+		},
+	})
+	if err != nil {
+		auditor.Logger.Panic(err)
+	}
+
+	for _, user := range result.GetValue() {
+		objectId = strings.ToLower(to.String(user.GetId()))
+		break
+	}
+
+	// save to cache
+	_ = auditor.cache.Add(cacheKey, objectId, auditor.cacheExpiry)
+
+	return
+}
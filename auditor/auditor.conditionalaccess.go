@@ -0,0 +1,232 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditConditionalAccess(ctx context.Context, logger *zap.SugaredLogger, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchConditionalAccess(ctx, logger)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.ConditionalAccess.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.ConditionalAccess.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.ConditionalAccess.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal ConditionalAccess policies", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.conditionalAccess)
+	}
+}
+
+func (auditor *AzureAuditor) fetchConditionalAccess(ctx context.Context, logger *zap.SugaredLogger) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+	client := auditor.azure.msGraph.ServiceClient()
+
+	result, err := client.Identity().ConditionalAccess().Policies().Get(ctx, nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		for _, policy := range result.GetValue() {
+			policyId := stringPtrToStringLower(policy.GetId())
+
+			obj := map[string]interface{}{
+				"resource.id": fmt.Sprintf("/identity/conditionalaccess/policies/%s", policyId),
+
+				"conditionalaccess.id":          policyId,
+				"conditionalaccess.displayname": to.String(policy.GetDisplayName()),
+				"conditionalaccess.description": to.String(policy.GetDescription()),
+				"conditionalaccess.templateid":  to.String(policy.GetTemplateId()),
+				"conditionalaccess.state":       "",
+			}
+
+			if policy.GetState() != nil {
+				obj["conditionalaccess.state"] = strings.ToLower(policy.GetState().String())
+			}
+
+			if policy.GetCreatedDateTime() != nil {
+				obj["conditionalaccess.createddatetime"] = policy.GetCreatedDateTime().Format(time.RFC3339)
+				obj["conditionalaccess.age"] = time.Since(*policy.GetCreatedDateTime())
+			}
+
+			if policy.GetModifiedDateTime() != nil {
+				obj["conditionalaccess.modifieddatetime"] = policy.GetModifiedDateTime().Format(time.RFC3339)
+			}
+
+			auditor.applyConditionalAccessConditions(ctx, logger, obj, policy.GetConditions())
+			applyConditionalAccessGrantControls(obj, policy.GetGrantControls())
+			applyConditionalAccessSessionControls(obj, policy.GetSessionControls())
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+
+		if result.GetOdataNextLink() == nil {
+			break
+		}
+
+		result, err = client.Identity().ConditionalAccess().Policies().WithUrl(*result.GetOdataNextLink()).Get(ctx, nil)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	return
+}
+
+func (auditor *AzureAuditor) applyConditionalAccessConditions(ctx context.Context, logger *zap.SugaredLogger, obj map[string]interface{}, conditions models.ConditionalAccessConditionSetable) {
+	obj["conditionalaccess.users.include"] = []string{}
+	obj["conditionalaccess.users.exclude"] = []string{}
+	obj["conditionalaccess.groups.include"] = []string{}
+	obj["conditionalaccess.groups.exclude"] = []string{}
+	obj["conditionalaccess.roles.include"] = []string{}
+	obj["conditionalaccess.roles.exclude"] = []string{}
+	obj["conditionalaccess.applications.include"] = []string{}
+	obj["conditionalaccess.applications.exclude"] = []string{}
+	obj["conditionalaccess.useractions.include"] = []string{}
+	obj["conditionalaccess.clientapptypes"] = []string{}
+
+	if conditions == nil {
+		return
+	}
+
+	if users := conditions.GetUsers(); users != nil {
+		obj["conditionalaccess.users.include"] = conditionalAccessIdList(users.GetIncludeUsers())
+		obj["conditionalaccess.users.exclude"] = conditionalAccessIdList(users.GetExcludeUsers())
+		obj["conditionalaccess.groups.include"] = conditionalAccessIdList(users.GetIncludeGroups())
+		obj["conditionalaccess.groups.exclude"] = conditionalAccessIdList(users.GetExcludeGroups())
+		obj["conditionalaccess.roles.include"] = conditionalAccessIdList(users.GetIncludeRoles())
+		obj["conditionalaccess.roles.exclude"] = conditionalAccessIdList(users.GetExcludeRoles())
+
+		// display names for excluded principals (eg. break-glass accounts)
+		obj["conditionalaccess.users.exclude.names"] = auditor.lookupConditionalAccessPrincipalNames(ctx, logger, users.GetExcludeUsers())
+		obj["conditionalaccess.groups.exclude.names"] = auditor.lookupConditionalAccessPrincipalNames(ctx, logger, users.GetExcludeGroups())
+	}
+
+	if applications := conditions.GetApplications(); applications != nil {
+		obj["conditionalaccess.applications.include"] = conditionalAccessIdList(applications.GetIncludeApplications())
+		obj["conditionalaccess.applications.exclude"] = conditionalAccessIdList(applications.GetExcludeApplications())
+		obj["conditionalaccess.useractions.include"] = conditionalAccessIdList(applications.GetIncludeUserActions())
+	}
+
+	clientAppTypeList := []string{}
+	for _, clientAppType := range conditions.GetClientAppTypes() {
+		clientAppTypeList = append(clientAppTypeList, strings.ToLower(clientAppType.String()))
+	}
+	obj["conditionalaccess.clientapptypes"] = clientAppTypeList
+}
+
+func applyConditionalAccessGrantControls(obj map[string]interface{}, grantControls models.ConditionalAccessGrantControlsable) {
+	obj["conditionalaccess.grantcontrols.builtin"] = []string{}
+	obj["conditionalaccess.grantcontrols.operator"] = ""
+	obj["conditionalaccess.grantcontrols.authenticationstrength"] = ""
+
+	if grantControls == nil {
+		return
+	}
+
+	builtInControlList := []string{}
+	for _, control := range grantControls.GetBuiltInControls() {
+		builtInControlList = append(builtInControlList, strings.ToLower(control.String()))
+	}
+	obj["conditionalaccess.grantcontrols.builtin"] = builtInControlList
+	obj["conditionalaccess.grantcontrols.operator"] = strings.ToLower(to.String(grantControls.GetOperator()))
+
+	if authenticationStrength := grantControls.GetAuthenticationStrength(); authenticationStrength != nil {
+		obj["conditionalaccess.grantcontrols.authenticationstrength"] = to.String(authenticationStrength.GetDisplayName())
+	}
+}
+
+func applyConditionalAccessSessionControls(obj map[string]interface{}, sessionControls models.ConditionalAccessSessionControlsable) {
+	obj["conditionalaccess.sessioncontrols.signinfrequency"] = ""
+	obj["conditionalaccess.sessioncontrols.persistentbrowser"] = ""
+	obj["conditionalaccess.sessioncontrols.cloudappsecurity"] = ""
+	obj["conditionalaccess.sessioncontrols.applicationenforcedrestrictions"] = "false"
+
+	if sessionControls == nil {
+		return
+	}
+
+	if signInFrequency := sessionControls.GetSignInFrequency(); signInFrequency != nil && signInFrequency.GetIsEnabled() != nil && *signInFrequency.GetIsEnabled() {
+		if signInFrequency.GetFrequencyInterval() != nil && strings.EqualFold(signInFrequency.GetFrequencyInterval().String(), "everyTime") {
+			obj["conditionalaccess.sessioncontrols.signinfrequency"] = "everytime"
+		} else if signInFrequency.GetValue() != nil && signInFrequency.GetTypeEscaped() != nil {
+			obj["conditionalaccess.sessioncontrols.signinfrequency"] = fmt.Sprintf("%v %v", *signInFrequency.GetValue(), strings.ToLower(signInFrequency.GetTypeEscaped().String()))
+		}
+	}
+
+	if persistentBrowser := sessionControls.GetPersistentBrowser(); persistentBrowser != nil && persistentBrowser.GetIsEnabled() != nil && *persistentBrowser.GetIsEnabled() && persistentBrowser.GetMode() != nil {
+		obj["conditionalaccess.sessioncontrols.persistentbrowser"] = strings.ToLower(persistentBrowser.GetMode().String())
+	}
+
+	if cloudAppSecurity := sessionControls.GetCloudAppSecurity(); cloudAppSecurity != nil && cloudAppSecurity.GetIsEnabled() != nil && *cloudAppSecurity.GetIsEnabled() && cloudAppSecurity.GetCloudAppSecurityType() != nil {
+		obj["conditionalaccess.sessioncontrols.cloudappsecurity"] = strings.ToLower(cloudAppSecurity.GetCloudAppSecurityType().String())
+	}
+
+	if restrictions := sessionControls.GetApplicationEnforcedRestrictions(); restrictions != nil && restrictions.GetIsEnabled() != nil && *restrictions.GetIsEnabled() {
+		obj["conditionalaccess.sessioncontrols.applicationenforcedrestrictions"] = "true"
+	}
+}
+
+// lookupConditionalAccessPrincipalNames returns the display names of the principals, special values (eg. "All", "GuestsOrExternalUsers") are kept
+func (auditor *AzureAuditor) lookupConditionalAccessPrincipalNames(ctx context.Context, logger *zap.SugaredLogger, idList []string) (list []string) {
+	list = []string{}
+
+	lookupIdList := []string{}
+	for _, id := range idList {
+		if uuid.Validate(id) == nil {
+			lookupIdList = append(lookupIdList, strings.ToLower(id))
+		}
+	}
+
+	principalMap := map[string]string{}
+	if len(lookupIdList) > 0 {
+		principalObjectMap, err := auditor.azure.msGraph.LookupPrincipalID(ctx, lookupIdList...)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for id, principal := range principalObjectMap {
+			if principal != nil && principal.DisplayName != "" {
+				principalMap[strings.ToLower(id)] = principal.DisplayName
+			}
+		}
+	}
+
+	for _, id := range idList {
+		if name, ok := principalMap[strings.ToLower(id)]; ok {
+			list = append(list, name)
+		} else {
+			list = append(list, id)
+		}
+	}
+
+	return
+}
+
+func conditionalAccessIdList(val []string) (list []string) {
+	list = []string{}
+	for _, row := range val {
+		list = append(list, strings.ToLower(row))
+	}
+	return
+}
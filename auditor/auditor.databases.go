@@ -0,0 +1,370 @@
+package auditor
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/mysql/armmysqlflexibleservers"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/postgresql/armpostgresqlflexibleservers/v4"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+const (
+	DatabaseTypeSqlServer          = "sqlserver"
+	DatabaseTypePostgreSqlFlexible = "postgresqlflexible"
+	DatabaseTypeMySqlFlexible      = "mysqlflexible"
+)
+
+type (
+	databaseFirewallRule struct {
+		ID      *string
+		Name    *string
+		StartIp string
+		EndIp   string
+	}
+)
+
+func (auditor *AzureAuditor) auditDatabases(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchDatabases(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.Databases.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.Databases.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.Databases.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal Databases", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.database)
+	}
+}
+
+func (auditor *AzureAuditor) fetchDatabases(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+	list = append(list, auditor.fetchSqlServers(ctx, logger, subscription)...)
+	list = append(list, auditor.fetchPostgreSqlFlexibleServers(ctx, logger, subscription)...)
+	list = append(list, auditor.fetchMySqlFlexibleServers(ctx, logger, subscription)...)
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// fetchSqlServers returns the Azure SQL servers and their firewall rules
+func (auditor *AzureAuditor) fetchSqlServers(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	serverClient, err := armsql.NewServersClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	auditingClient, err := armsql.NewServerBlobAuditingPoliciesClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	firewallClient, err := armsql.NewFirewallRulesClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := serverClient.NewListPager(&armsql.ServersClientListOptions{
+		Expand: to.StringPtr("administrators/activedirectory"),
+	})
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Warnf("unable to list SQL servers: %v", err)
+			return
+		}
+
+		for _, server := range result.Value {
+			if server.Properties == nil {
+				continue
+			}
+
+			serverObj := newDatabaseServerObject(subscription, server.ID, DatabaseTypeSqlServer)
+			serverObj["database.version"] = to.String(server.Properties.Version)
+			serverObj["database.minimaltlsversion"] = normalizeTlsVersion(to.String(server.Properties.MinimalTLSVersion))
+			serverObj["database.publicnetworkaccess"] = stringPtrToStringLower((*string)(server.Properties.PublicNetworkAccess))
+			if server.Properties.Administrators != nil {
+				serverObj["database.entraonlyauthentication"] = boolPtrToString(server.Properties.Administrators.AzureADOnlyAuthentication)
+			}
+
+			resourceGroup, serverName := serverObj["resourcegroup.name"].(string), serverObj["database.servername"].(string)
+
+			auditingSettings, err := auditingClient.Get(ctx, resourceGroup, serverName, nil)
+			if err == nil && auditingSettings.Properties != nil {
+				serverObj["database.auditing"] = stringPtrToStringLower((*string)(auditingSettings.Properties.State))
+			} else if err != nil && azureResponseStatusCode(err) != http.StatusNotFound {
+				logger.Warnf("unable to fetch auditing settings of SQL server %v: %v", serverName, err)
+			}
+
+			firewallRuleList := []databaseFirewallRule{}
+			firewallPager := firewallClient.NewListByServerPager(resourceGroup, serverName, nil)
+			for firewallPager.More() {
+				firewallResult, err := firewallPager.NextPage(ctx)
+				if err != nil {
+					logger.Warnf("unable to list firewall rules of SQL server %v, skipping server: %v", serverName, err)
+					firewallRuleList = nil
+					break
+				}
+
+				for _, firewallRule := range firewallResult.Value {
+					if firewallRule.Properties == nil {
+						continue
+					}
+
+					firewallRuleList = append(firewallRuleList, databaseFirewallRule{
+						ID:      firewallRule.ID,
+						Name:    firewallRule.Name,
+						StartIp: to.String(firewallRule.Properties.StartIPAddress),
+						EndIp:   to.String(firewallRule.Properties.EndIPAddress),
+					})
+				}
+			}
+
+			if firewallRuleList != nil {
+				list = append(list, newDatabaseAzureObjectList(serverObj, firewallRuleList)...)
+			}
+		}
+	}
+
+	return
+}
+
+// fetchPostgreSqlFlexibleServers returns the Azure Database for PostgreSQL flexible servers and their firewall rules
+func (auditor *AzureAuditor) fetchPostgreSqlFlexibleServers(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	serverClient, err := armpostgresqlflexibleservers.NewServersClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	configurationClient, err := armpostgresqlflexibleservers.NewConfigurationsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	firewallClient, err := armpostgresqlflexibleservers.NewFirewallRulesClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := serverClient.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Warnf("unable to list PostgreSQL flexible servers: %v", err)
+			return
+		}
+
+		for _, server := range result.Value {
+			if server.Properties == nil {
+				continue
+			}
+
+			serverObj := newDatabaseServerObject(subscription, server.ID, DatabaseTypePostgreSqlFlexible)
+			serverObj["database.version"] = to.String((*string)(server.Properties.Version))
+			if server.Properties.Network != nil {
+				serverObj["database.publicnetworkaccess"] = stringPtrToStringLower((*string)(server.Properties.Network.PublicNetworkAccess))
+			}
+
+			if server.Properties.AuthConfig != nil {
+				entraOnly := strings.EqualFold(to.String((*string)(server.Properties.AuthConfig.ActiveDirectoryAuth)), "enabled") && strings.EqualFold(to.String((*string)(server.Properties.AuthConfig.PasswordAuth)), "disabled")
+				serverObj["database.entraonlyauthentication"] = strconv.FormatBool(entraOnly)
+			}
+
+			resourceGroup, serverName := serverObj["resourcegroup.name"].(string), serverObj["database.servername"].(string)
+
+			configuration, err := configurationClient.Get(ctx, resourceGroup, serverName, "ssl_min_protocol_version", nil)
+			if err == nil && configuration.Properties != nil {
+				serverObj["database.minimaltlsversion"] = normalizeTlsVersion(to.String(configuration.Properties.Value))
+			} else if err != nil && azureResponseStatusCode(err) != http.StatusNotFound {
+				logger.Warnf("unable to fetch configuration of PostgreSQL server %v: %v", serverName, err)
+			}
+
+			firewallRuleList := []databaseFirewallRule{}
+			firewallPager := firewallClient.NewListByServerPager(resourceGroup, serverName, nil)
+			for firewallPager.More() {
+				firewallResult, err := firewallPager.NextPage(ctx)
+				if err != nil {
+					logger.Warnf("unable to list firewall rules of PostgreSQL server %v, skipping server: %v", serverName, err)
+					firewallRuleList = nil
+					break
+				}
+
+				for _, firewallRule := range firewallResult.Value {
+					if firewallRule.Properties == nil {
+						continue
+					}
+
+					firewallRuleList = append(firewallRuleList, databaseFirewallRule{
+						ID:      firewallRule.ID,
+						Name:    firewallRule.Name,
+						StartIp: to.String(firewallRule.Properties.StartIPAddress),
+						EndIp:   to.String(firewallRule.Properties.EndIPAddress),
+					})
+				}
+			}
+
+			if firewallRuleList != nil {
+				list = append(list, newDatabaseAzureObjectList(serverObj, firewallRuleList)...)
+			}
+		}
+	}
+
+	return
+}
+
+// fetchMySqlFlexibleServers returns the Azure Database for MySQL flexible servers and their firewall rules
+func (auditor *AzureAuditor) fetchMySqlFlexibleServers(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	serverClient, err := armmysqlflexibleservers.NewServersClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	configurationClient, err := armmysqlflexibleservers.NewConfigurationsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	firewallClient, err := armmysqlflexibleservers.NewFirewallRulesClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	// requestConfiguration returns the value of a server configuration (parameter), empty if not available
+	requestConfiguration := func(resourceGroup, serverName, name string) string {
+		configuration, err := configurationClient.Get(ctx, resourceGroup, serverName, name, nil)
+		if err != nil {
+			if azureResponseStatusCode(err) != http.StatusNotFound {
+				logger.Warnf("unable to fetch configuration %v of MySQL server %v: %v", name, serverName, err)
+			}
+			return ""
+		}
+
+		if configuration.Properties == nil {
+			return ""
+		}
+
+		return to.String(configuration.Properties.Value)
+	}
+
+	pager := serverClient.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Warnf("unable to list MySQL flexible servers: %v", err)
+			return
+		}
+
+		for _, server := range result.Value {
+			if server.Properties == nil {
+				continue
+			}
+
+			serverObj := newDatabaseServerObject(subscription, server.ID, DatabaseTypeMySqlFlexible)
+			serverObj["database.version"] = to.String((*string)(server.Properties.Version))
+			if server.Properties.Network != nil {
+				serverObj["database.publicnetworkaccess"] = stringPtrToStringLower((*string)(server.Properties.Network.PublicNetworkAccess))
+			}
+
+			resourceGroup, serverName := serverObj["resourcegroup.name"].(string), serverObj["database.servername"].(string)
+
+			serverObj["database.entraonlyauthentication"] = strconv.FormatBool(strings.EqualFold(requestConfiguration(resourceGroup, serverName, "aad_auth_only"), "on"))
+			serverObj["database.minimaltlsversion"] = normalizeTlsVersion(requestConfiguration(resourceGroup, serverName, "tls_version"))
+
+			firewallRuleList := []databaseFirewallRule{}
+			firewallPager := firewallClient.NewListByServerPager(resourceGroup, serverName, nil)
+			for firewallPager.More() {
+				firewallResult, err := firewallPager.NextPage(ctx)
+				if err != nil {
+					logger.Warnf("unable to list firewall rules of MySQL server %v, skipping server: %v", serverName, err)
+					firewallRuleList = nil
+					break
+				}
+
+				for _, firewallRule := range firewallResult.Value {
+					if firewallRule.Properties == nil {
+						continue
+					}
+
+					firewallRuleList = append(firewallRuleList, databaseFirewallRule{
+						ID:      firewallRule.ID,
+						Name:    firewallRule.Name,
+						StartIp: to.String(firewallRule.Properties.StartIPAddress),
+						EndIp:   to.String(firewallRule.Properties.EndIPAddress),
+					})
+				}
+			}
+
+			if firewallRuleList != nil {
+				list = append(list, newDatabaseAzureObjectList(serverObj, firewallRuleList)...)
+			}
+		}
+	}
+
+	return
+}
+
+func newDatabaseServerObject(subscription *armsubscriptions.Subscription, serverID *string, databaseType string) map[string]interface{} {
+	resourceID := stringPtrToStringLower(serverID)
+	azureResource, _ := azureCommon.ParseResourceId(resourceID)
+
+	return map[string]interface{}{
+		"resource.id":        resourceID,
+		"subscription.id":    to.String(subscription.SubscriptionID),
+		"resourcegroup.name": azureResource.ResourceGroup,
+
+		"database.objecttype":              "server",
+		"database.type":                    databaseType,
+		"database.servername":              azureResource.ResourceName,
+		"database.version":                 "",
+		"database.minimaltlsversion":       "",
+		"database.publicnetworkaccess":     "",
+		"database.entraonlyauthentication": "false",
+		"database.auditing":                "",
+	}
+}
+
+// newDatabaseAzureObjectList returns the server object and one object per firewall rule (including the server settings)
+func newDatabaseAzureObjectList(serverObj map[string]interface{}, firewallRuleList []databaseFirewallRule) (list []*validator.AzureObject) {
+	for _, firewallRule := range firewallRuleList {
+		obj := map[string]interface{}{}
+		for key, val := range serverObj {
+			obj[key] = val
+		}
+
+		obj["resource.id"] = stringPtrToStringLower(firewallRule.ID)
+		obj["database.objecttype"] = "firewallrule"
+		obj["firewall.name"] = to.String(firewallRule.Name)
+		obj["firewall.startip"] = firewallRule.StartIp
+		obj["firewall.endip"] = firewallRule.EndIp
+		// special rule "allow access to Azure services" (any azure ip, including other customers)
+		obj["firewall.allowallazure"] = strconv.FormatBool(firewallRule.StartIp == "0.0.0.0" && firewallRule.EndIp == "0.0.0.0")
+		obj["firewall.allowall"] = strconv.FormatBool(firewallRule.StartIp == "0.0.0.0" && firewallRule.EndIp == "255.255.255.255")
+
+		list = append(list, validator.NewAzureObject(obj))
+	}
+
+	serverObj["database.firewallrulecount"] = int64(len(firewallRuleList))
+	list = append(list, validator.NewAzureObject(serverObj))
+
+	return
+}
@@ -0,0 +1,150 @@
+package auditor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/security/armsecurity"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditDefenderPlans(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list, secureScore := auditor.fetchDefenderPlans(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.DefenderPlans.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.DefenderPlans.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.DefenderPlans.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal Defender plans", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.defenderPlan)
+
+		if secureScore != nil {
+			auditor.prometheus.defenderSecureScore.With(prometheus.Labels{
+				"subscriptionID":   to.String(subscription.SubscriptionID),
+				"subscriptionName": to.String(subscription.DisplayName),
+			}).Set(*secureScore)
+		}
+	}
+}
+
+func (auditor *AzureAuditor) fetchDefenderPlans(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject, secureScore *float64) {
+	list = []*validator.AzureObject{}
+
+	secureScoreResult := auditor.fetchDefenderSecureScore(ctx, logger, subscription)
+	if secureScoreResult != nil && secureScoreResult.Properties != nil && secureScoreResult.Properties.Score != nil {
+		score := secureScoreResult.Properties.Score
+		if score.Percentage != nil {
+			// percentage is returned as fraction (0..1)
+			val := *score.Percentage * 100
+			secureScore = &val
+		} else if score.Current != nil && score.Max != nil && *score.Max > 0 {
+			val := *score.Current / float64(*score.Max) * 100
+			secureScore = &val
+		}
+	}
+
+	for planName, pricing := range auditor.getDefenderPricingList(ctx, logger, subscription) {
+		obj := map[string]interface{}{
+			"resource.id":     stringPtrToStringLower(pricing.ID),
+			"subscription.id": to.String(subscription.SubscriptionID),
+
+			"defender.plan.name":           planName,
+			"defender.plan.tier":           stringPtrToStringLower((*string)(pricing.Properties.PricingTier)),
+			"defender.plan.subplan":        stringPtrToStringLower(pricing.Properties.SubPlan),
+			"defender.plan.enforce":        stringPtrToStringLower((*string)(pricing.Properties.Enforce)),
+			"defender.plan.inherited":      stringPtrToStringLower((*string)(pricing.Properties.Inherited)),
+			"defender.plan.inheritedfrom":  stringPtrToStringLower(pricing.Properties.InheritedFrom),
+			"defender.plan.enablementtime": "",
+		}
+
+		if pricing.Properties.EnablementTime != nil {
+			obj["defender.plan.enablementtime"] = pricing.Properties.EnablementTime.Format(time.RFC3339)
+		}
+
+		if secureScore != nil {
+			obj["defender.securescore"] = *secureScore
+		}
+
+		list = append(list, validator.NewAzureObject(obj))
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// fetchDefenderSecureScore returns the overall (ascScore) secure score of the subscription, nil if not available
+func (auditor *AzureAuditor) fetchDefenderSecureScore(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) *armsecurity.SecureScoreItem {
+	client, err := armsecurity.NewSecureScoresClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	result, err := client.Get(ctx, "ascScore", nil)
+	if err != nil {
+		// eg. no permissions, secure score not (yet) calculated or Microsoft.Security provider is not registered
+		logger.Warnf("unable to fetch Defender secure score: %v", err)
+		return nil
+	}
+
+	return &result.SecureScoreItem
+}
+
+// getDefenderPricingList returns the Microsoft Defender for Cloud plans (pricings) of the subscription indexed by the (lowercased) plan name
+func (auditor *AzureAuditor) getDefenderPricingList(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list map[string]*armsecurity.Pricing) {
+	auditor.locks.defender.Lock()
+	defer auditor.locks.defender.Unlock()
+
+	list = map[string]*armsecurity.Pricing{}
+
+	cacheKey := "defenderpricings:" + *subscription.SubscriptionID
+	if val, ok := auditor.cache.Get(cacheKey); ok {
+		// fetched from cache
+		list = val.(map[string]*armsecurity.Pricing)
+		return
+	}
+
+	client, err := armsecurity.NewPricingsClient(auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	result, err := client.List(ctx, strings.TrimPrefix(*subscription.ID, "/"), nil)
+	if err != nil {
+		// eg. no permissions or Microsoft.Security provider is not registered, not cached so it's retried on next run
+		logger.Warnf("unable to fetch Defender plans: %v", err)
+		return
+	}
+
+	for _, pricing := range result.Value {
+		if pricing == nil || pricing.Properties == nil {
+			continue
+		}
+
+		list[strings.ToLower(to.String(pricing.Name))] = pricing
+	}
+
+	auditor.Logger.Infof("found %v Defender plans for Subscription %v (%v) (cache update)", len(list), to.String(subscription.DisplayName), to.String(subscription.SubscriptionID))
+
+	// save to cache
+	_ = auditor.cache.Add(cacheKey, list, auditor.cacheExpiry)
+
+	return
+}
@@ -0,0 +1,112 @@
+package auditor
+
+import (
+	"context"
+	"strings"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditDenyAssignments(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchDenyAssignments(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.DenyAssignments.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.DenyAssignments.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.DenyAssignments.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal DenyAssignments", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.denyAssignment)
+	}
+}
+
+func (auditor *AzureAuditor) fetchDenyAssignments(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	client, err := armauthorization.NewDenyAssignmentsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, denyAssignment := range result.Value {
+			if denyAssignment.Properties == nil {
+				continue
+			}
+
+			scopeResourceId := strings.ToLower(to.String(denyAssignment.Properties.Scope))
+			azureScope, scopeType := parseAuthorizationScope(scopeResourceId)
+
+			actionList := []string{}
+			notActionList := []string{}
+			dataActionList := []string{}
+			notDataActionList := []string{}
+			for _, permission := range denyAssignment.Properties.Permissions {
+				actionList = append(actionList, to.Slice(permission.Actions)...)
+				notActionList = append(notActionList, to.Slice(permission.NotActions)...)
+				dataActionList = append(dataActionList, to.Slice(permission.DataActions)...)
+				notDataActionList = append(notDataActionList, to.Slice(permission.NotDataActions)...)
+			}
+
+			excludePrincipalList := []string{}
+			for _, principal := range denyAssignment.Properties.ExcludePrincipals {
+				excludePrincipalList = append(excludePrincipalList, stringPtrToStringLower(principal.ID))
+			}
+
+			// one object per denied principal (eg. everyone)
+			principalList := denyAssignment.Properties.Principals
+			if len(principalList) == 0 {
+				// deny assignment without principals, report it with empty principal
+				principalList = []*armauthorization.Principal{{}}
+			}
+
+			for _, principal := range principalList {
+				obj := map[string]interface{}{
+					"resource.id":        stringPtrToStringLower(denyAssignment.ID),
+					"subscription.id":    to.String(subscription.SubscriptionID),
+					"principal.objectid": stringPtrToStringLower(principal.ID),
+					"resourcegroup.name": azureScope.ResourceGroup,
+
+					"denyassignment.name":                    to.String(denyAssignment.Properties.DenyAssignmentName),
+					"denyassignment.description":             to.String(denyAssignment.Properties.Description),
+					"denyassignment.scope":                   scopeResourceId,
+					"denyassignment.scopetype":               scopeType,
+					"denyassignment.systemprotected":         boolPtrToString(denyAssignment.Properties.IsSystemProtected),
+					"denyassignment.donotapplytochildscopes": boolPtrToString(denyAssignment.Properties.DoNotApplyToChildScopes),
+					"denyassignment.actions":                 actionList,
+					"denyassignment.notactions":              notActionList,
+					"denyassignment.dataactions":             dataActionList,
+					"denyassignment.notdataactions":          notDataActionList,
+					"denyassignment.excludeprincipals":       excludePrincipalList,
+					"denyassignment.principal.type":          to.String(principal.Type),
+					"denyassignment.principal.displayname":   to.String(principal.DisplayName),
+				}
+
+				list = append(list, validator.NewAzureObject(obj))
+			}
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
@@ -0,0 +1,183 @@
+package auditor
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/monitor/armmonitor"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+const (
+	DiagnosticSettingsMaxRetries = 6
+	DiagnosticSettingsRetryDelay = 5 * time.Second
+)
+
+func (auditor *AzureAuditor) auditDiagnosticSettings(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchDiagnosticSettings(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.DiagnosticSettings.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.DiagnosticSettings.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.DiagnosticSettings.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal DiagnosticSettings", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.diagnosticSetting)
+	}
+}
+
+func (auditor *AzureAuditor) fetchDiagnosticSettings(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	// diagnostic settings are requested per resource, throttled requests (429) are retried with backoff
+	clientOptions := auditor.azure.client.NewArmClientOptions()
+	clientOptions.Retry.MaxRetries = DiagnosticSettingsMaxRetries
+	clientOptions.Retry.RetryDelay = DiagnosticSettingsRetryDelay
+	client, err := armmonitor.NewDiagnosticSettingsClient(auditor.azure.client.GetCred(), clientOptions)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	var (
+		wg       sync.WaitGroup
+		listLock sync.Mutex
+	)
+
+	concurrency := auditor.Opts.DiagnosticSettings.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	concurrencyLimit := make(chan struct{}, concurrency)
+
+	for resourceID, resource := range auditor.getResourceList(ctx, subscription) {
+		wg.Add(1)
+		concurrencyLimit <- struct{}{}
+		go func(resourceID string, resource *armresources.GenericResourceExpanded) {
+			defer func() {
+				<-concurrencyLimit
+				wg.Done()
+			}()
+
+			obj, ok := auditor.fetchDiagnosticSettingsForResource(ctx, logger, client, subscription, resourceID, resource)
+			if !ok {
+				return
+			}
+
+			listLock.Lock()
+			defer listLock.Unlock()
+			list = append(list, validator.NewAzureObject(obj))
+		}(resourceID, resource)
+	}
+	wg.Wait()
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// fetchDiagnosticSettingsForResource builds the object of the resource with its diagnostic settings,
+// returns false if the diagnostic settings couldn't be fetched and the resource should be skipped
+func (auditor *AzureAuditor) fetchDiagnosticSettingsForResource(ctx context.Context, logger *zap.SugaredLogger, client *armmonitor.DiagnosticSettingsClient, subscription *armsubscriptions.Subscription, resourceID string, resource *armresources.GenericResourceExpanded) (map[string]interface{}, bool) {
+	azureResource, _ := azureCommon.ParseResourceId(resourceID)
+
+	obj := map[string]interface{}{
+		"resource.id":        resourceID,
+		"subscription.id":    to.String(subscription.SubscriptionID),
+		"resourcegroup.name": azureResource.ResourceGroup,
+
+		"diagnostics.resourcetype": stringPtrToStringLower(resource.Type),
+		"diagnostics.supported":    "true",
+	}
+
+	nameList := []string{}
+	workspaceIdList := []string{}
+	storageAccountIdList := []string{}
+	eventHubAuthorizationRuleIdList := []string{}
+	categoryList := []string{}
+	metricCategoryList := []string{}
+
+	pager := client.NewListPager(resourceID, nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			switch azureResponseStatusCode(err) {
+			case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed:
+				// resource type doesn't support diagnostic settings
+				logger.Debugf("diagnostic settings not supported for resource %v", strings.ToLower(resourceID))
+				obj["diagnostics.supported"] = "false"
+			default:
+				logger.Warnf("unable to fetch diagnostic settings for resource %v, skipping resource: %v", strings.ToLower(resourceID), err)
+				return nil, false
+			}
+			break
+		}
+
+		for _, diagnosticSetting := range result.Value {
+			if diagnosticSetting.Properties == nil {
+				continue
+			}
+
+			nameList = append(nameList, to.String(diagnosticSetting.Name))
+
+			if val := stringPtrToStringLower(diagnosticSetting.Properties.WorkspaceID); val != "" {
+				workspaceIdList = append(workspaceIdList, val)
+			}
+
+			if val := stringPtrToStringLower(diagnosticSetting.Properties.StorageAccountID); val != "" {
+				storageAccountIdList = append(storageAccountIdList, val)
+			}
+
+			if val := stringPtrToStringLower(diagnosticSetting.Properties.EventHubAuthorizationRuleID); val != "" {
+				eventHubAuthorizationRuleIdList = append(eventHubAuthorizationRuleIdList, val)
+			}
+
+			for _, log := range diagnosticSetting.Properties.Logs {
+				if log == nil || log.Enabled == nil || !*log.Enabled {
+					continue
+				}
+
+				if log.Category != nil {
+					categoryList = append(categoryList, to.String(log.Category))
+				} else if log.CategoryGroup != nil {
+					categoryList = append(categoryList, to.String(log.CategoryGroup))
+				}
+			}
+
+			for _, metric := range diagnosticSetting.Properties.Metrics {
+				if metric != nil && metric.Enabled != nil && *metric.Enabled {
+					metricCategoryList = append(metricCategoryList, to.String(metric.Category))
+				}
+			}
+		}
+	}
+
+	obj["diagnostics.count"] = int64(len(nameList))
+	obj["diagnostics.names"] = nameList
+	obj["diagnostics.workspaceids"] = workspaceIdList
+	obj["diagnostics.storageaccountids"] = storageAccountIdList
+	obj["diagnostics.eventhubauthorizationruleids"] = eventHubAuthorizationRuleIdList
+	obj["diagnostics.categories"] = categoryList
+	obj["diagnostics.metrics"] = metricCategoryList
+
+	return obj, true
+}
@@ -0,0 +1,105 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/microsoftgraph/msgraph-sdk-go/models"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditDirectoryRoles(ctx context.Context, logger *zap.SugaredLogger, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchDirectoryRoles(ctx, logger)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.DirectoryRoles.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.DirectoryRoles.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.DirectoryRoles.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal DirectoryRole members", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.directoryRole)
+	}
+}
+
+func (auditor *AzureAuditor) fetchDirectoryRoles(ctx context.Context, logger *zap.SugaredLogger) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+	client := auditor.azure.msGraph.ServiceClient()
+
+	// only activated directory roles are returned, roles without any members are not activated
+	result, err := client.DirectoryRoles().Get(ctx, nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		for _, directoryRole := range result.GetValue() {
+			directoryRoleId := stringPtrToStringLower(directoryRole.GetId())
+
+			for _, member := range auditor.fetchDirectoryRoleMembers(ctx, logger, directoryRoleId) {
+				principalObjectId := stringPtrToStringLower(member.GetId())
+
+				obj := map[string]interface{}{
+					"resource.id":        fmt.Sprintf("/directoryroles/%s/members/%s", directoryRoleId, principalObjectId),
+					"principal.objectid": principalObjectId,
+
+					"directoryrole.id":          directoryRoleId,
+					"directoryrole.name":        to.String(directoryRole.GetDisplayName()),
+					"directoryrole.description": to.String(directoryRole.GetDescription()),
+					"directoryrole.templateid":  stringPtrToStringLower(directoryRole.GetRoleTemplateId()),
+				}
+
+				list = append(list, validator.NewAzureObject(obj))
+			}
+		}
+
+		if result.GetOdataNextLink() == nil {
+			break
+		}
+
+		result, err = client.DirectoryRoles().WithUrl(*result.GetOdataNextLink()).Get(ctx, nil)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	auditor.enrichAzureObjectsWithMsGraphPrincipals(ctx, &list)
+
+	return
+}
+
+func (auditor *AzureAuditor) fetchDirectoryRoleMembers(ctx context.Context, logger *zap.SugaredLogger, directoryRoleId string) (list []models.DirectoryObjectable) {
+	client := auditor.azure.msGraph.ServiceClient()
+
+	result, err := client.DirectoryRoles().ByDirectoryRoleId(directoryRoleId).Members().Get(ctx, nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		list = append(list, result.GetValue()...)
+
+		if result.GetOdataNextLink() == nil {
+			break
+		}
+
+		result, err = client.DirectoryRoles().ByDirectoryRoleId(directoryRoleId).Members().WithUrl(*result.GetOdataNextLink()).Get(ctx, nil)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	return
+}
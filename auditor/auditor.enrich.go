@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 	"github.com/webdevops/go-common/utils/to"
 
@@ -185,3 +186,24 @@ func (auditor *AzureAuditor) enrichAzureObjectsWithMsGraphPrincipals(ctx context
 		}
 	}
 }
+
+func (auditor *AzureAuditor) enrichAzureObjectsWithRoleDefinitionsByScope(ctx context.Context, scope string, list *[]*validator.AzureObject) {
+	// roledefinition ids differ between scopes, so the definitions are matched by their name (guid)
+	roleDefinitionList := map[string]*armauthorization.RoleDefinition{}
+	for _, roleDefinition := range auditor.getRoleDefinitionListByScope(ctx, scope) {
+		roleDefinitionList[strings.ToLower(to.String(roleDefinition.Name))] = roleDefinition
+	}
+
+	for key, row := range *list {
+		obj := (*(*list)[key])
+
+		if roleDefinitionId, ok := (*row)["roledefinition.id"].(string); ok && roleDefinitionId != "" {
+			roleDefinitionName := strings.ToLower(roleDefinitionId[strings.LastIndex(roleDefinitionId, "/")+1:])
+			if roleDefinition, ok := roleDefinitionList[roleDefinitionName]; ok {
+				obj["roledefinition.name"] = to.String(roleDefinition.Properties.RoleName)
+				obj["roledefinition.type"] = to.String(roleDefinition.Properties.RoleType)
+				obj["roledefinition.description"] = to.String(roleDefinition.Properties.Description)
+			}
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/msi/armmsi"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/microsoftgraph/msgraph-sdk-go/applications"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditFederatedCredentials(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchManagedIdentityFederatedCredentials(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.FederatedCredentials.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.FederatedCredentials.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.FederatedCredentials.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal FederatedCredentials", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.federatedCredential)
+	}
+}
+
+func (auditor *AzureAuditor) auditApplicationFederatedCredentials(ctx context.Context, logger *zap.SugaredLogger, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchApplicationFederatedCredentials(ctx, logger)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.FederatedCredentials.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.FederatedCredentials.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.FederatedCredentials.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal Application FederatedCredentials", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.federatedCredentialApp)
+	}
+}
+
+// fetchApplicationFederatedCredentials returns the federated identity credentials of all app registrations
+func (auditor *AzureAuditor) fetchApplicationFederatedCredentials(ctx context.Context, logger *zap.SugaredLogger) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	client := auditor.azure.msGraph.ServiceClient()
+
+	result, err := client.Applications().Get(ctx, &applications.ApplicationsRequestBuilderGetRequestConfiguration{
+		QueryParameters: &applications.ApplicationsRequestBuilderGetQueryParameters{
+			Select: []string{"id", "appId", "displayName"},
+			Expand: []string{"federatedIdentityCredentials"},
+		},
+	})
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		for _, application := range result.GetValue() {
+			applicationObjectId := stringPtrToStringLower(application.GetId())
+
+			for _, credential := range application.GetFederatedIdentityCredentials() {
+				obj := map[string]interface{}{
+					"resource.id": fmt.Sprintf("/applications/%s/federatedidentitycredentials/%s", applicationObjectId, stringPtrToStringLower(credential.GetId())),
+
+					"identity.type":        "application",
+					"identity.id":          applicationObjectId,
+					"identity.clientid":    stringPtrToStringLower(application.GetAppId()),
+					"identity.displayname": to.String(application.GetDisplayName()),
+
+					"federatedcredential.name":        to.String(credential.GetName()),
+					"federatedcredential.description": to.String(credential.GetDescription()),
+					"federatedcredential.issuer":      to.String(credential.GetIssuer()),
+					"federatedcredential.subject":     to.String(credential.GetSubject()),
+					"federatedcredential.audiences":   credential.GetAudiences(),
+				}
+
+				list = append(list, validator.NewAzureObject(obj))
+			}
+		}
+
+		if result.GetOdataNextLink() == nil {
+			break
+		}
+
+		result, err = client.Applications().WithUrl(*result.GetOdataNextLink()).Get(ctx, nil)
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, nil, &list)
+
+	return
+}
+
+// fetchManagedIdentityFederatedCredentials returns the federated identity credentials of all user-assigned managed identities of the subscription
+func (auditor *AzureAuditor) fetchManagedIdentityFederatedCredentials(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	identityClient, err := armmsi.NewUserAssignedIdentitiesClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	credentialClient, err := armmsi.NewFederatedIdentityCredentialsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := identityClient.NewListBySubscriptionPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Warnf("unable to list user-assigned managed identities: %v", err)
+			break
+		}
+
+		for _, identity := range result.Value {
+			resourceID := stringPtrToStringLower(identity.ID)
+			azureResource, _ := azureCommon.ParseResourceId(resourceID)
+
+			identityClientId := ""
+			if identity.Properties != nil {
+				identityClientId = stringPtrToStringLower(identity.Properties.ClientID)
+			}
+
+			credentialPager := credentialClient.NewListPager(azureResource.ResourceGroup, azureResource.ResourceName, nil)
+			for credentialPager.More() {
+				credentialResult, err := credentialPager.NextPage(ctx)
+				if err != nil {
+					logger.Warnf("unable to list federated identity credentials of %v, skipping identity: %v", resourceID, err)
+					break
+				}
+
+				for _, credential := range credentialResult.Value {
+					if credential.Properties == nil {
+						continue
+					}
+
+					obj := map[string]interface{}{
+						"resource.id":        stringPtrToStringLower(credential.ID),
+						"subscription.id":    to.String(subscription.SubscriptionID),
+						"resourcegroup.name": azureResource.ResourceGroup,
+
+						"identity.type":        "managedidentity",
+						"identity.id":          resourceID,
+						"identity.clientid":    identityClientId,
+						"identity.displayname": azureResource.ResourceName,
+
+						"federatedcredential.name":        to.String(credential.Name),
+						"federatedcredential.description": "",
+						"federatedcredential.issuer":      to.String(credential.Properties.Issuer),
+						"federatedcredential.subject":     to.String(credential.Properties.Subject),
+						"federatedcredential.audiences":   to.Slice(credential.Properties.Audiences),
+					}
+
+					list = append(list, validator.NewAzureObject(obj))
+				}
+			}
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
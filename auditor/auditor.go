@@ -27,10 +27,31 @@ import (
 
 const (
 	ReportKeyvaultAccessPolicies   = "KeyvaultAccessPolicy"
+	ReportNetworkSecurityGroups    = "NetworkSecurityGroup"
 	ReportResourceProviders        = "ResourceProvider"
 	ReportResourceProviderFeatures = "ResourceProviderFeature"
 	ReportResourceGroups           = "ResourceGroup"
 	ReportRoleAssignments          = "RoleAssignment"
+	ReportRoleAssignmentsMgmtGroup = "RoleAssignment:ManagementGroup"
+	ReportStorageAccounts          = "StorageAccount"
+	ReportDenyAssignments          = "DenyAssignment"
+	ReportClassicAdministrators    = "ClassicAdministrator"
+	ReportApplications             = "Application"
+	ReportPolicyCompliance         = "PolicyCompliance"
+	ReportDiagnosticSettings       = "DiagnosticSetting"
+	ReportResourceLocks            = "ResourceLock"
+	ReportKeyvaultSettings         = "KeyvaultSettings"
+	ReportKeyvaultItems            = "KeyvaultItem"
+	ReportRoleDefinitions          = "RoleDefinition"
+	ReportResources                = "Resource"
+	ReportSubscriptions            = "Subscription"
+	ReportDefenderPlans            = "DefenderPlan"
+	ReportConditionalAccess        = "ConditionalAccess"
+	ReportDirectoryRoles           = "DirectoryRole"
+	ReportFederatedCredentials     = "FederatedCredential"
+	ReportFederatedCredentialsApp  = "FederatedCredential:Application"
+	ReportPublicIPs                = "PublicIP"
+	ReportDatabases                = "Database"
 	ReportResourceGraph            = "ResourceGraph:%v"
 	ReportLogAnalytics             = "LogAnalytics:%v"
 )
@@ -52,9 +73,12 @@ type (
 		}
 
 		locks struct {
-			subscriptions  sync.Mutex
-			resourceGroups sync.Mutex
-			resources      sync.Mutex
+			subscriptions    sync.Mutex
+			resourceGroups   sync.Mutex
+			resources        sync.Mutex
+			managementGroups sync.Mutex
+			keyvaults        sync.Mutex
+			defender         sync.Mutex
 		}
 
 		cron *cron.Cron
@@ -68,6 +92,10 @@ type (
 
 		metricsLock *sync.RWMutex
 
+		// role definitions (by name) assignable outside of a single subscription (eg. management groups)
+		// which were already audited in the current RoleDefinitions run
+		roleDefinitionsAudited *sync.Map
+
 		prometheus auditorPrometheus
 	}
 )
@@ -78,6 +106,7 @@ func NewAzureAuditor() *AzureAuditor {
 	auditor.reportUncommited = map[string]*AzureAuditorReport{}
 	auditor.reportLock = &sync.RWMutex{}
 	auditor.metricsLock = &sync.RWMutex{}
+	auditor.roleDefinitionsAudited = &sync.Map{}
 	return &auditor
 }
 
@@ -89,6 +118,7 @@ func (auditor *AzureAuditor) Init() {
 	auditor.initCron()
 
 	validator.Logger = auditor.Logger
+	validator.FuncTimeout = auditor.Opts.Rules.FuncTimeout
 }
 
 func (auditor *AzureAuditor) GetConfig() AuditConfig {
@@ -164,6 +194,21 @@ func (auditor *AzureAuditor) start() {
 		)
 	}
 
+	if auditor.isManagementGroupRoleAssignmentAuditEnabled() {
+		auditor.addCronjob(
+			ReportRoleAssignmentsMgmtGroup,
+			auditor.Opts.Cronjobs.RoleAssignmentsManagementGroups,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				// RoleAssignments config (and rule stats) is shared with the subscription report
+				// and reset by its cronjob, resetting it here would zero the stats while it is running
+			},
+			auditor.auditRoleAssignmentsByManagementGroup,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.roleAssignmentMgmtGroup.Reset()
+			},
+		)
+	}
+
 	if cronspecIsValid(auditor.Opts.Cronjobs.KeyvaultAccessPolicies) && auditor.config.KeyvaultAccessPolicies.IsEnabled() {
 		auditor.addCronjobBySubscription(
 			ReportKeyvaultAccessPolicies,
@@ -178,6 +223,20 @@ func (auditor *AzureAuditor) start() {
 		)
 	}
 
+	if cronspecIsValid(auditor.Opts.Cronjobs.NetworkSecurityGroups) && auditor.config.NetworkSecurityGroups.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportNetworkSecurityGroups,
+			auditor.Opts.Cronjobs.NetworkSecurityGroups,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.NetworkSecurityGroups.Reset()
+			},
+			auditor.auditNetworkSecurityGroups,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.networkSecurityGroup.Reset()
+			},
+		)
+	}
+
 	if cronspecIsValid(auditor.Opts.Cronjobs.ResourceProvider) && auditor.config.ResourceProviders.IsEnabled() {
 		auditor.addCronjobBySubscription(
 			ReportResourceProviders,
@@ -206,6 +265,273 @@ func (auditor *AzureAuditor) start() {
 		)
 	}
 
+	if cronspecIsValid(auditor.Opts.Cronjobs.StorageAccounts) && auditor.config.StorageAccounts.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportStorageAccounts,
+			auditor.Opts.Cronjobs.StorageAccounts,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.StorageAccounts.Reset()
+			},
+			auditor.auditStorageAccounts,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.storageAccount.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.DenyAssignments) && auditor.config.DenyAssignments.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportDenyAssignments,
+			auditor.Opts.Cronjobs.DenyAssignments,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.DenyAssignments.Reset()
+			},
+			auditor.auditDenyAssignments,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.denyAssignment.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.ClassicAdministrators) && auditor.config.ClassicAdministrators.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportClassicAdministrators,
+			auditor.Opts.Cronjobs.ClassicAdministrators,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.ClassicAdministrators.Reset()
+			},
+			auditor.auditClassicAdministrators,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.classicAdministrator.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.Applications) && auditor.config.Applications.IsEnabled() {
+		auditor.addCronjob(
+			ReportApplications,
+			auditor.Opts.Cronjobs.Applications,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.Applications.Reset()
+			},
+			auditor.auditApplications,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.application.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.PolicyCompliance) && auditor.config.PolicyCompliance.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportPolicyCompliance,
+			auditor.Opts.Cronjobs.PolicyCompliance,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.PolicyCompliance.Reset()
+			},
+			auditor.auditPolicyCompliance,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.policyCompliance.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.DiagnosticSettings) && auditor.config.DiagnosticSettings.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportDiagnosticSettings,
+			auditor.Opts.Cronjobs.DiagnosticSettings,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.DiagnosticSettings.Reset()
+			},
+			auditor.auditDiagnosticSettings,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.diagnosticSetting.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.ResourceLocks) && auditor.config.ResourceLocks.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportResourceLocks,
+			auditor.Opts.Cronjobs.ResourceLocks,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.ResourceLocks.Reset()
+			},
+			auditor.auditResourceLocks,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.resourceLock.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.KeyvaultSettings) && auditor.config.KeyvaultSettings.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportKeyvaultSettings,
+			auditor.Opts.Cronjobs.KeyvaultSettings,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.KeyvaultSettings.Reset()
+			},
+			auditor.auditKeyvaultSettings,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.keyvaultSettings.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.KeyvaultItems) && auditor.config.KeyvaultItems.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportKeyvaultItems,
+			auditor.Opts.Cronjobs.KeyvaultItems,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.KeyvaultItems.Reset()
+			},
+			auditor.auditKeyvaultItems,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.keyvaultItem.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.RoleDefinitions) && auditor.config.RoleDefinitions.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportRoleDefinitions,
+			auditor.Opts.Cronjobs.RoleDefinitions,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.RoleDefinitions.Reset()
+				auditor.roleDefinitionsAudited = &sync.Map{}
+			},
+			auditor.auditRoleDefinitions,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.roleDefinition.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.Resources) && auditor.config.Resources.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportResources,
+			auditor.Opts.Cronjobs.Resources,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.Resources.Reset()
+			},
+			auditor.auditResources,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.resource.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.Subscriptions) && auditor.config.Subscriptions.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportSubscriptions,
+			auditor.Opts.Cronjobs.Subscriptions,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.Subscriptions.Reset()
+			},
+			auditor.auditSubscriptions,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.subscription.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.DefenderPlans) && auditor.config.DefenderPlans.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportDefenderPlans,
+			auditor.Opts.Cronjobs.DefenderPlans,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.DefenderPlans.Reset()
+			},
+			auditor.auditDefenderPlans,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.defenderPlan.Reset()
+				auditor.prometheus.defenderSecureScore.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.ConditionalAccess) && auditor.config.ConditionalAccess.IsEnabled() {
+		auditor.addCronjob(
+			ReportConditionalAccess,
+			auditor.Opts.Cronjobs.ConditionalAccess,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.ConditionalAccess.Reset()
+			},
+			auditor.auditConditionalAccess,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.conditionalAccess.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.DirectoryRoles) && auditor.config.DirectoryRoles.IsEnabled() {
+		auditor.addCronjob(
+			ReportDirectoryRoles,
+			auditor.Opts.Cronjobs.DirectoryRoles,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.DirectoryRoles.Reset()
+			},
+			auditor.auditDirectoryRoles,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.directoryRole.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.FederatedCredentials) && auditor.config.FederatedCredentials.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportFederatedCredentials,
+			auditor.Opts.Cronjobs.FederatedCredentials,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.FederatedCredentials.Reset()
+			},
+			auditor.auditFederatedCredentials,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.federatedCredential.Reset()
+			},
+		)
+
+		auditor.addCronjob(
+			ReportFederatedCredentialsApp,
+			auditor.Opts.Cronjobs.FederatedCredentials,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				// FederatedCredentials config (and rule stats) is shared with the subscription report
+				// and reset by its cronjob, resetting it here would zero the stats while it is running
+			},
+			auditor.auditApplicationFederatedCredentials,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.federatedCredentialApp.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.PublicIPs) && auditor.config.PublicIPs.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportPublicIPs,
+			auditor.Opts.Cronjobs.PublicIPs,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.PublicIPs.Reset()
+			},
+			auditor.auditPublicIPs,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.publicIP.Reset()
+			},
+		)
+	}
+
+	if cronspecIsValid(auditor.Opts.Cronjobs.Databases) && auditor.config.Databases.IsEnabled() {
+		auditor.addCronjobBySubscription(
+			ReportDatabases,
+			auditor.Opts.Cronjobs.Databases,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.config.Databases.Reset()
+			},
+			auditor.auditDatabases,
+			func(ctx context.Context, logger *zap.SugaredLogger) {
+				auditor.prometheus.database.Reset()
+			},
+		)
+	}
+
 	if cronspecIsValid(auditor.Opts.Cronjobs.ResourceGraph) && auditor.config.ResourceGraph.IsEnabled() {
 		for key, queryConfig := range auditor.config.ResourceGraph.Queries {
 			queryName := key
@@ -267,6 +593,12 @@ func (auditor *AzureAuditor) start() {
 	}()
 }
 
+// isManagementGroupRoleAssignmentAuditEnabled returns true if RoleAssignments are audited on management group level
+// (assignments on management group and root scope are then excluded from the subscription report)
+func (auditor *AzureAuditor) isManagementGroupRoleAssignmentAuditEnabled() bool {
+	return cronspecIsValid(auditor.Opts.Cronjobs.RoleAssignmentsManagementGroups) && auditor.config.RoleAssignments.IsEnabled()
+}
+
 func (auditor *AzureAuditor) addCronjob(name string, cronSpec string, startupCallback func(ctx context.Context, logger *zap.SugaredLogger), callback func(ctx context.Context, logger *zap.SugaredLogger, report *AzureAuditorReport, callback chan<- func()), finishCallback func(ctx context.Context, logger *zap.SugaredLogger)) {
 	contextLogger := auditor.Logger.With(zap.String("report", name))
 	contextLogger.Infof("scheduling %v audit report cronjob with spec \"%v\"", name, cronSpec)
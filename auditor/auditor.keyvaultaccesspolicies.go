@@ -20,12 +20,12 @@ func (auditor *AzureAuditor) auditKeyvaultAccessPolicies(ctx context.Context, lo
 	violationMetric := prometheusCommon.NewMetricsList()
 
 	for _, object := range list {
-		matchingRuleId, status := auditor.config.KeyvaultAccessPolicies.Validate(object)
-		report.Add(object, matchingRuleId, status)
+		result := auditor.config.KeyvaultAccessPolicies.Validate(object)
+		report.Add(object, result)
 
-		if status.IsDeny() && auditor.config.KeyvaultAccessPolicies.IsMetricsEnabled() {
+		if result.Status.IsDeny() && auditor.config.KeyvaultAccessPolicies.IsMetricsEnabled() {
 			violationMetric.AddInfo(
-				auditor.config.KeyvaultAccessPolicies.CreatePrometheusMetricFromAzureObject(object, matchingRuleId),
+				auditor.config.KeyvaultAccessPolicies.CreatePrometheusMetricFromAzureObject(object, result),
 			)
 		}
 	}
@@ -37,47 +37,32 @@ func (auditor *AzureAuditor) auditKeyvaultAccessPolicies(ctx context.Context, lo
 }
 
 func (auditor *AzureAuditor) fetchKeyvaultAccessPolicies(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
-	client, err := armkeyvault.NewVaultsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
-	if err != nil {
-		logger.Panic(err)
-	}
-
-	pager := client.NewListPager(nil)
-	for pager.More() {
-		result, err := pager.NextPage(ctx)
-		if err != nil {
-			logger.Panic(err)
-		}
-
-		for _, item := range result.Value {
-			resourceInfo, _ := azureCommon.ParseResourceId(to.String(item.ID))
-
-			keyvaultResource, err := client.Get(ctx, resourceInfo.ResourceGroup, resourceInfo.ResourceName, nil)
-			if err != nil {
-				logger.Panic(err)
-			}
-
-			if keyvaultResource.Properties.AccessPolicies != nil {
-				for _, accessPolicy := range keyvaultResource.Properties.AccessPolicies {
-					azureResource, _ := azureCommon.ParseResourceId(*item.ID)
+	// access policies are audited with current data (not the shared cache) as changes must be reported promptly
+	for _, keyvaultResource := range auditor.refreshKeyvaultList(ctx, subscription) {
+		if keyvaultResource.Properties != nil && keyvaultResource.Properties.AccessPolicies != nil {
+			for _, accessPolicy := range keyvaultResource.Properties.AccessPolicies {
+				if accessPolicy == nil || accessPolicy.Permissions == nil {
+					continue
+				}
 
-					obj := map[string]interface{}{
-						"resource.id":             stringPtrToStringLower(item.ID),
-						"subscription.id":         to.String(subscription.SubscriptionID),
-						"resourcegroup.name":      azureResource.ResourceGroup,
-						"principal.applicationid": stringPtrToStringLower(accessPolicy.ApplicationID),
-						"principal.objectid":      stringPtrToStringLower(accessPolicy.ObjectID),
+				azureResource, _ := azureCommon.ParseResourceId(*keyvaultResource.ID)
 
-						"keyvault.name": azureResource.ResourceName,
+				obj := map[string]interface{}{
+					"resource.id":             stringPtrToStringLower(keyvaultResource.ID),
+					"subscription.id":         to.String(subscription.SubscriptionID),
+					"resourcegroup.name":      azureResource.ResourceGroup,
+					"principal.applicationid": stringPtrToStringLower(accessPolicy.ApplicationID),
+					"principal.objectid":      stringPtrToStringLower(accessPolicy.ObjectID),
 
-						"permissions.certificates": keyvaultCertificatePermissionsToStringList(accessPolicy.Permissions.Certificates),
-						"permissions.secrets":      keyvaultSecretPermissionsToStringList(accessPolicy.Permissions.Secrets),
-						"permissions.keys":         keyvaultKeyPermissionsToStringList(accessPolicy.Permissions.Keys),
-						"permissions.storage":      keyvaultStoragePermissionsToStringList(accessPolicy.Permissions.Storage),
-					}
+					"keyvault.name": azureResource.ResourceName,
 
-					list = append(list, validator.NewAzureObject(obj))
+					"permissions.certificates": keyvaultCertificatePermissionsToStringList(accessPolicy.Permissions.Certificates),
+					"permissions.secrets":      keyvaultSecretPermissionsToStringList(accessPolicy.Permissions.Secrets),
+					"permissions.keys":         keyvaultKeyPermissionsToStringList(accessPolicy.Permissions.Keys),
+					"permissions.storage":      keyvaultStoragePermissionsToStringList(accessPolicy.Permissions.Storage),
 				}
+
+				list = append(list, validator.NewAzureObject(obj))
 			}
 		}
 	}
@@ -0,0 +1,223 @@
+package auditor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azsecrets"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+type (
+	keyvaultItem struct {
+		ID          string
+		Type        string
+		Name        string
+		Enabled     *bool
+		ContentType string
+		Created     *time.Time
+		Expires     *time.Time
+		NotBefore   *time.Time
+	}
+)
+
+func (auditor *AzureAuditor) auditKeyvaultItems(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchKeyvaultItems(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.KeyvaultItems.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.KeyvaultItems.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.KeyvaultItems.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal KeyVault items", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.keyvaultItem)
+	}
+}
+
+func (auditor *AzureAuditor) fetchKeyvaultItems(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	for _, keyvaultResource := range auditor.getKeyvaultList(ctx, subscription) {
+		if keyvaultResource.Properties == nil || keyvaultResource.Properties.VaultURI == nil {
+			continue
+		}
+
+		azureResource, _ := azureCommon.ParseResourceId(*keyvaultResource.ID)
+		vaultLogger := logger.With(zap.String("keyvault", azureResource.ResourceName))
+
+		itemList, err := auditor.fetchKeyvaultItemList(ctx, *keyvaultResource.Properties.VaultURI)
+		if err != nil {
+			// data plane might not be reachable (access denied, private endpoint, dns) for every keyvault,
+			// don't fail the whole report but report the keyvault as not inspectable
+			itemError := err.Error()
+			var responseErr *azcore.ResponseError
+			if errors.As(err, &responseErr) {
+				itemError = responseErr.ErrorCode
+				if itemError == "" {
+					itemError = http.StatusText(responseErr.StatusCode)
+				}
+			}
+			vaultLogger.Warnf("unable to list keyvault items, skipping keyvault: %v", err)
+
+			list = append(list, validator.NewAzureObject(map[string]interface{}{
+				"resource.id":        stringPtrToStringLower(keyvaultResource.ID),
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"resourcegroup.name": azureResource.ResourceGroup,
+
+				"keyvault.name": azureResource.ResourceName,
+
+				"item.type":        "vault",
+				"item.name":        "",
+				"item.enabled":     "",
+				"item.contenttype": "",
+				"item.expires":     "",
+				"item.notbefore":   "",
+				"item.permanent":   "",
+				"item.inspectable": "false",
+				"item.error":       itemError,
+			}))
+			continue
+		}
+
+		for _, item := range itemList {
+			obj := map[string]interface{}{
+				"resource.id":        strings.ToLower(item.ID),
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"resourcegroup.name": azureResource.ResourceGroup,
+
+				"keyvault.name": azureResource.ResourceName,
+
+				"item.type":        item.Type,
+				"item.name":        item.Name,
+				"item.enabled":     boolPtrToString(item.Enabled),
+				"item.contenttype": item.ContentType,
+				"item.expires":     "",
+				"item.notbefore":   "",
+				"item.permanent":   "true",
+				"item.inspectable": "true",
+				"item.error":       "",
+			}
+
+			if item.Created != nil {
+				obj["item.createdon"] = item.Created.Format(time.RFC3339)
+				obj["item.age"] = time.Since(*item.Created)
+			}
+
+			if item.Expires != nil {
+				obj["item.expires"] = item.Expires.Format(time.RFC3339)
+				obj["item.expiry"] = time.Until(*item.Expires)
+				obj["item.permanent"] = "false"
+			}
+
+			if item.NotBefore != nil {
+				obj["item.notbefore"] = item.NotBefore.Format(time.RFC3339)
+			}
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// fetchKeyvaultItemList returns the metadata of all secrets, keys and certificates of the keyvault (values are never fetched)
+func (auditor *AzureAuditor) fetchKeyvaultItemList(ctx context.Context, vaultUrl string) (list []keyvaultItem, err error) {
+	secretClient, err := azsecrets.NewClient(vaultUrl, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secretPager := secretClient.NewListSecretPropertiesPager(nil)
+	for secretPager.More() {
+		result, err := secretPager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, secret := range result.Value {
+			if secret.ID == nil {
+				continue
+			}
+
+			// certificates are exposed as managed secrets (and managed keys)
+			itemType := "secret"
+			if secret.Managed != nil && *secret.Managed {
+				itemType = "certificate"
+			}
+
+			item := keyvaultItem{
+				ID:          string(*secret.ID),
+				Type:        itemType,
+				Name:        secret.ID.Name(),
+				ContentType: to.String(secret.ContentType),
+			}
+			if secret.Attributes != nil {
+				item.Enabled = secret.Attributes.Enabled
+				item.Created = secret.Attributes.Created
+				item.Expires = secret.Attributes.Expires
+				item.NotBefore = secret.Attributes.NotBefore
+			}
+
+			list = append(list, item)
+		}
+	}
+
+	keyClient, err := azkeys.NewClient(vaultUrl, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPager := keyClient.NewListKeyPropertiesPager(nil)
+	for keyPager.More() {
+		result, err := keyPager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range result.Value {
+			// managed keys are backing certificates, which are already reported via secrets
+			if key.KID == nil || (key.Managed != nil && *key.Managed) {
+				continue
+			}
+
+			item := keyvaultItem{
+				ID:   string(*key.KID),
+				Type: "key",
+				Name: key.KID.Name(),
+			}
+			if key.Attributes != nil {
+				item.Enabled = key.Attributes.Enabled
+				item.Created = key.Attributes.Created
+				item.Expires = key.Attributes.Expires
+				item.NotBefore = key.Attributes.NotBefore
+			}
+
+			list = append(list, item)
+		}
+	}
+
+	return
+}
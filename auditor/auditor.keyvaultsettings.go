@@ -0,0 +1,87 @@
+package auditor
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditKeyvaultSettings(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchKeyvaultSettings(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.KeyvaultSettings.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.KeyvaultSettings.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.KeyvaultSettings.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal KeyVault settings", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.keyvaultSettings)
+	}
+}
+
+func (auditor *AzureAuditor) fetchKeyvaultSettings(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	for _, keyvaultResource := range auditor.getKeyvaultList(ctx, subscription) {
+		if keyvaultResource.Properties == nil {
+			continue
+		}
+
+		azureResource, _ := azureCommon.ParseResourceId(to.String(keyvaultResource.ID))
+		properties := keyvaultResource.Properties
+
+		obj := map[string]interface{}{
+			"resource.id":        stringPtrToStringLower(keyvaultResource.ID),
+			"subscription.id":    to.String(subscription.SubscriptionID),
+			"resourcegroup.name": azureResource.ResourceGroup,
+
+			"keyvault.name":                         azureResource.ResourceName,
+			"keyvault.location":                     stringPtrToStringLower(keyvaultResource.Location),
+			"keyvault.enablerbacauthorization":      boolPtrToString(properties.EnableRbacAuthorization),
+			"keyvault.enablesoftdelete":             boolPtrToString(properties.EnableSoftDelete),
+			"keyvault.softdeleteretentionindays":    int32PtrToInt64(properties.SoftDeleteRetentionInDays),
+			"keyvault.enablepurgeprotection":        boolPtrToString(properties.EnablePurgeProtection),
+			"keyvault.enabledfordeployment":         boolPtrToString(properties.EnabledForDeployment),
+			"keyvault.enabledfordiskencryption":     boolPtrToString(properties.EnabledForDiskEncryption),
+			"keyvault.enabledfortemplatedeployment": boolPtrToString(properties.EnabledForTemplateDeployment),
+			"keyvault.publicnetworkaccess":          stringPtrToStringLower(properties.PublicNetworkAccess),
+			"keyvault.privateendpointcount":         int64(len(properties.PrivateEndpointConnections)),
+			"keyvault.accesspolicycount":            int64(len(properties.AccessPolicies)),
+		}
+
+		if properties.SKU != nil {
+			obj["keyvault.sku"] = stringPtrToStringLower((*string)(properties.SKU.Name))
+		}
+
+		if networkAcls := properties.NetworkACLs; networkAcls != nil {
+			ipRuleList := []string{}
+			for _, ipRule := range networkAcls.IPRules {
+				ipRuleList = append(ipRuleList, to.String(ipRule.Value))
+			}
+
+			obj["keyvault.networkacls.defaultaction"] = stringPtrToStringLower((*string)(networkAcls.DefaultAction))
+			obj["keyvault.networkacls.bypass"] = stringPtrToStringLower((*string)(networkAcls.Bypass))
+			obj["keyvault.networkacls.iprules"] = ipRuleList
+		}
+
+		list = append(list, validator.NewAzureObject(obj))
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
@@ -0,0 +1,127 @@
+package auditor
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditNetworkSecurityGroups(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchNetworkSecurityGroups(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.NetworkSecurityGroups.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.NetworkSecurityGroups.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.NetworkSecurityGroups.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal NetworkSecurityGroup rules", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.networkSecurityGroup)
+	}
+}
+
+func (auditor *AzureAuditor) fetchNetworkSecurityGroups(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	client, err := armnetwork.NewSecurityGroupsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListAllPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, securityGroup := range result.Value {
+			if securityGroup.Properties == nil {
+				continue
+			}
+
+			azureResource, _ := azureCommon.ParseResourceId(to.String(securityGroup.ID))
+
+			subnetList := []string{}
+			for _, subnet := range securityGroup.Properties.Subnets {
+				subnetList = append(subnetList, stringPtrToStringLower(subnet.ID))
+			}
+
+			networkInterfaceList := []string{}
+			for _, networkInterface := range securityGroup.Properties.NetworkInterfaces {
+				networkInterfaceList = append(networkInterfaceList, stringPtrToStringLower(networkInterface.ID))
+			}
+
+			securityRules := map[string][]*armnetwork.SecurityRule{
+				"custom":  securityGroup.Properties.SecurityRules,
+				"default": securityGroup.Properties.DefaultSecurityRules,
+			}
+
+			for ruleType, ruleList := range securityRules {
+				for _, securityRule := range ruleList {
+					if securityRule.Properties == nil {
+						continue
+					}
+
+					obj := map[string]interface{}{
+						"resource.id":        stringPtrToStringLower(securityRule.ID),
+						"subscription.id":    to.String(subscription.SubscriptionID),
+						"resourcegroup.name": azureResource.ResourceGroup,
+
+						"networksecuritygroup.name":              azureResource.ResourceName,
+						"networksecuritygroup.location":          stringPtrToStringLower(securityGroup.Location),
+						"networksecuritygroup.subnets":           subnetList,
+						"networksecuritygroup.networkinterfaces": networkInterfaceList,
+
+						"securityrule.name":                       to.String(securityRule.Name),
+						"securityrule.type":                       ruleType,
+						"securityrule.description":                to.String(securityRule.Properties.Description),
+						"securityrule.direction":                  stringPtrToStringLower((*string)(securityRule.Properties.Direction)),
+						"securityrule.access":                     stringPtrToStringLower((*string)(securityRule.Properties.Access)),
+						"securityrule.protocol":                   stringPtrToStringLower((*string)(securityRule.Properties.Protocol)),
+						"securityrule.priority":                   int32PtrToInt64(securityRule.Properties.Priority),
+						"securityrule.sourceaddressprefixes":      networkSecurityRulePrefixList(securityRule.Properties.SourceAddressPrefix, securityRule.Properties.SourceAddressPrefixes),
+						"securityrule.sourceportranges":           networkSecurityRulePrefixList(securityRule.Properties.SourcePortRange, securityRule.Properties.SourcePortRanges),
+						"securityrule.destinationaddressprefixes": networkSecurityRulePrefixList(securityRule.Properties.DestinationAddressPrefix, securityRule.Properties.DestinationAddressPrefixes),
+						"securityrule.destinationportranges":      networkSecurityRulePrefixList(securityRule.Properties.DestinationPortRange, securityRule.Properties.DestinationPortRanges),
+					}
+
+					list = append(list, validator.NewAzureObject(obj))
+				}
+			}
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// networkSecurityRulePrefixList merges the single and the list variant of a security rule prefix/port range
+func networkSecurityRulePrefixList(val *string, valList []*string) (list []string) {
+	list = []string{}
+	if val != nil && *val != "" {
+		list = append(list, *val)
+	}
+
+	for _, row := range valList {
+		if row != nil && *row != "" {
+			list = append(list, *row)
+		}
+	}
+	return
+}
@@ -0,0 +1,139 @@
+package auditor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+const (
+	// policy states are fetched via ResourceGraph, only non compliant states are relevant for the audit
+	PolicyComplianceResourceGraphQuery = `policyresources
+| where type =~ "microsoft.policyinsights/policystates"
+| where properties.complianceState =~ "NonCompliant"
+| project
+	resourceId = tostring(properties.resourceId),
+	resourceType = tostring(properties.resourceType),
+	resourceLocation = tostring(properties.resourceLocation),
+	complianceState = tostring(properties.complianceState),
+	complianceReasonCode = tostring(properties.complianceReasonCode),
+	policyAssignmentId = tostring(properties.policyAssignmentId),
+	policyAssignmentName = tostring(properties.policyAssignmentName),
+	policyAssignmentScope = tostring(properties.policyAssignmentScope),
+	policyDefinitionId = tostring(properties.policyDefinitionId),
+	policyDefinitionName = tostring(properties.policyDefinitionName),
+	policyDefinitionAction = tostring(properties.policyDefinitionAction),
+	policyDefinitionReferenceId = tostring(properties.policyDefinitionReferenceId),
+	policySetDefinitionId = tostring(properties.policySetDefinitionId),
+	policySetDefinitionName = tostring(properties.policySetDefinitionName),
+	timestamp = tostring(properties.timestamp)`
+)
+
+func (auditor *AzureAuditor) auditPolicyCompliance(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchPolicyCompliance(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.PolicyCompliance.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.PolicyCompliance.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.PolicyCompliance.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal PolicyCompliance states", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.policyCompliance)
+	}
+}
+
+func (auditor *AzureAuditor) fetchPolicyCompliance(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	client, err := armresourcegraph.NewClient(auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	queryFormat := armresourcegraph.ResultFormatObjectArray
+	queryTop := int32(ResourceGraphQueryOptionsTop)
+	queryRequest := armresourcegraph.QueryRequest{
+		Query: to.StringPtr(PolicyComplianceResourceGraphQuery),
+		Options: &armresourcegraph.QueryRequestOptions{
+			ResultFormat: &queryFormat,
+			Top:          &queryTop,
+		},
+		Subscriptions: []*string{subscription.SubscriptionID},
+	}
+
+	result, err := client.Resources(ctx, queryRequest, nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for {
+		if resultList, ok := result.Data.([]interface{}); ok {
+			// check if we got data, otherwise break the for loop
+			if len(resultList) == 0 {
+				break
+			}
+
+			for _, v := range resultList {
+				if row, ok := v.(map[string]interface{}); ok {
+					resourceId := strings.ToLower(interfaceToString(row["resourceId"]))
+					azureResource, _ := azureCommon.ParseResourceId(resourceId)
+
+					obj := map[string]interface{}{
+						"resource.id":        resourceId,
+						"subscription.id":    to.String(subscription.SubscriptionID),
+						"resourcegroup.name": azureResource.ResourceGroup,
+
+						"policy.resource.type":          strings.ToLower(interfaceToString(row["resourceType"])),
+						"policy.resource.location":      strings.ToLower(interfaceToString(row["resourceLocation"])),
+						"policy.compliancestate":        strings.ToLower(interfaceToString(row["complianceState"])),
+						"policy.compliancereason":       interfaceToString(row["complianceReasonCode"]),
+						"policy.assignment.id":          strings.ToLower(interfaceToString(row["policyAssignmentId"])),
+						"policy.assignment.name":        interfaceToString(row["policyAssignmentName"]),
+						"policy.assignment.scope":       strings.ToLower(interfaceToString(row["policyAssignmentScope"])),
+						"policy.definition.id":          strings.ToLower(interfaceToString(row["policyDefinitionId"])),
+						"policy.definition.name":        interfaceToString(row["policyDefinitionName"]),
+						"policy.definition.effect":      strings.ToLower(interfaceToString(row["policyDefinitionAction"])),
+						"policy.definition.referenceid": interfaceToString(row["policyDefinitionReferenceId"]),
+						"policy.setdefinition.id":       strings.ToLower(interfaceToString(row["policySetDefinitionId"])),
+						"policy.setdefinition.name":     interfaceToString(row["policySetDefinitionName"]),
+						"policy.evaluationtimestamp":    interfaceToString(row["timestamp"]),
+					}
+
+					list = append(list, validator.NewAzureObject(obj))
+				}
+			}
+		}
+
+		if result.SkipToken != nil {
+			queryRequest.Options.SkipToken = result.SkipToken
+			result, err = client.Resources(ctx, queryRequest, nil)
+			if err != nil {
+				logger.Panic(err)
+			}
+		} else {
+			break
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
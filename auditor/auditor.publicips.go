@@ -0,0 +1,141 @@
+package auditor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditPublicIPs(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchPublicIPs(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.PublicIPs.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.PublicIPs.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.PublicIPs.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal PublicIPs", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.publicIP)
+	}
+}
+
+func (auditor *AzureAuditor) fetchPublicIPs(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	client, err := armnetwork.NewPublicIPAddressesClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListAllPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, publicIP := range result.Value {
+			if publicIP.Properties == nil {
+				continue
+			}
+
+			azureResource, _ := azureCommon.ParseResourceId(to.String(publicIP.ID))
+
+			obj := map[string]interface{}{
+				"resource.id":        stringPtrToStringLower(publicIP.ID),
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"resourcegroup.name": azureResource.ResourceGroup,
+
+				"publicip.name":             azureResource.ResourceName,
+				"publicip.location":         stringPtrToStringLower(publicIP.Location),
+				"publicip.ipaddress":        to.String(publicIP.Properties.IPAddress),
+				"publicip.version":          stringPtrToStringLower((*string)(publicIP.Properties.PublicIPAddressVersion)),
+				"publicip.allocationmethod": stringPtrToStringLower((*string)(publicIP.Properties.PublicIPAllocationMethod)),
+				"publicip.zones":            to.Slice(publicIP.Zones),
+				"publicip.sku.name":         "",
+				"publicip.sku.tier":         "",
+				"publicip.dns.label":        "",
+				"publicip.dns.fqdn":         "",
+				"publicip.ddos.mode":        "",
+				"publicip.ddos.plan":        "",
+			}
+
+			if publicIP.SKU != nil {
+				obj["publicip.sku.name"] = stringPtrToStringLower((*string)(publicIP.SKU.Name))
+				obj["publicip.sku.tier"] = stringPtrToStringLower((*string)(publicIP.SKU.Tier))
+			}
+
+			if publicIP.Properties.DNSSettings != nil {
+				obj["publicip.dns.label"] = stringPtrToStringLower(publicIP.Properties.DNSSettings.DomainNameLabel)
+				obj["publicip.dns.fqdn"] = stringPtrToStringLower(publicIP.Properties.DNSSettings.Fqdn)
+			}
+
+			if publicIP.Properties.DdosSettings != nil {
+				obj["publicip.ddos.mode"] = stringPtrToStringLower((*string)(publicIP.Properties.DdosSettings.ProtectionMode))
+				if publicIP.Properties.DdosSettings.DdosProtectionPlan != nil {
+					obj["publicip.ddos.plan"] = stringPtrToStringLower(publicIP.Properties.DdosSettings.DdosProtectionPlan.ID)
+				}
+			}
+
+			associationType, associationID := publicIPAssociation(publicIP)
+			obj["publicip.association.type"] = associationType
+			obj["publicip.association.id"] = associationID
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// publicIPAssociation detects the resource the public ip is attached to (eg. networkinterface, loadbalancer, firewall, natgateway or unattached)
+func publicIPAssociation(publicIP *armnetwork.PublicIPAddress) (associationType, associationID string) {
+	associationType = "unattached"
+
+	if publicIP.Properties.IPConfiguration != nil && publicIP.Properties.IPConfiguration.ID != nil {
+		associationID = stringPtrToStringLower(publicIP.Properties.IPConfiguration.ID)
+		if azureResource, err := azureCommon.ParseResourceId(associationID); err == nil {
+			associationID = strings.ToLower(azureResource.ResourceId())
+		}
+
+		switch {
+		case strings.Contains(associationID, "/providers/microsoft.network/networkinterfaces/"):
+			associationType = "networkinterface"
+		case strings.Contains(associationID, "/providers/microsoft.network/loadbalancers/"):
+			associationType = "loadbalancer"
+		case strings.Contains(associationID, "/providers/microsoft.network/azurefirewalls/"):
+			associationType = "firewall"
+		case strings.Contains(associationID, "/providers/microsoft.network/applicationgateways/"):
+			associationType = "applicationgateway"
+		case strings.Contains(associationID, "/providers/microsoft.network/virtualnetworkgateways/"):
+			associationType = "virtualnetworkgateway"
+		case strings.Contains(associationID, "/providers/microsoft.network/bastionhosts/"):
+			associationType = "bastion"
+		default:
+			associationType = "other"
+		}
+	} else if publicIP.Properties.NatGateway != nil && publicIP.Properties.NatGateway.ID != nil {
+		associationType = "natgateway"
+		associationID = stringPtrToStringLower(publicIP.Properties.NatGateway.ID)
+	}
+
+	return
+}
@@ -33,14 +33,26 @@ type (
 		Ignore int64
 		Deny   int64
 		Allow  int64
+		Exempt int64
 	}
 
 	AzureAuditorReportLine struct {
-		Resource AzureAuditorReportLineResource `json:"resource"`
-		RuleID   string                         `json:"rule"`
-		GroupBy  interface{}                    `json:"groupBy"`
-		Status   string                         `json:"status"`
-		Count    uint64                         `json:"count"`
+		Resource    AzureAuditorReportLineResource `json:"resource"`
+		RuleID      string                         `json:"rule"`
+		Description string                         `json:"description"`
+		Severity    string                         `json:"severity"`
+		Owner       string                         `json:"owner"`
+		Remediation string                         `json:"remediation"`
+		References  []string                       `json:"references"`
+		GroupBy     interface{}                    `json:"groupBy"`
+		Status      string                         `json:"status"`
+		Count       uint64                         `json:"count"`
+
+		ExemptionReason     string `json:"exemptionReason"`
+		ExemptionApprovedBy string `json:"exemptionApprovedBy"`
+		ExemptionExpires    string `json:"exemptionExpires"`
+
+		Error string `json:"error"`
 	}
 
 	AzureAuditorReportLineResource map[string]interface{}
@@ -65,9 +77,18 @@ func (reportLine *AzureAuditorReportLine) MarshalJSON() ([]byte, error) {
 	resourceInfo, _ := reportLine.Resource.MarshalJSON()
 	data["resource"] = yamlCleanupRegexp.ReplaceAllString(string(resourceInfo), "$1: $2")
 	data["rule"] = reportLine.RuleID
+	data["description"] = reportLine.Description
+	data["severity"] = reportLine.Severity
+	data["owner"] = reportLine.Owner
+	data["remediation"] = reportLine.Remediation
+	data["references"] = reportLine.References
 	data["status"] = reportLine.Status
 	data["groupBy"] = reportLine.GroupBy
 	data["count"] = reportLine.Count
+	data["exemptionReason"] = reportLine.ExemptionReason
+	data["exemptionApprovedBy"] = reportLine.ExemptionApprovedBy
+	data["exemptionExpires"] = reportLine.ExemptionExpires
+	data["error"] = reportLine.Error
 
 	return json.Marshal(data)
 }
@@ -79,26 +100,39 @@ func (report *AzureAuditorReport) Clear() {
 	report.Lines = []*AzureAuditorReportLine{}
 }
 
-func (report *AzureAuditorReport) Add(resource *validator.AzureObject, ruleID string, status types.RuleStatus) {
+func (report *AzureAuditorReport) Add(resource *validator.AzureObject, result *validator.AuditConfigValidationResult) {
 	report.lock.Lock()
 	defer report.lock.Unlock()
 
-	report.Lines = append(
-		report.Lines,
-		&AzureAuditorReportLine{
-			Resource: AzureAuditorReportLineResource(*resource),
-			RuleID:   ruleID,
-			Status:   status.String(),
-		},
-	)
+	reportLine := &AzureAuditorReportLine{
+		Resource:    AzureAuditorReportLineResource(*resource),
+		RuleID:      result.RuleID,
+		Description: result.Metadata.Description,
+		Severity:    result.Metadata.Severity,
+		Owner:       result.Metadata.Owner,
+		Remediation: result.Metadata.Remediation,
+		References:  result.Metadata.References,
+		Status:      result.Status.String(),
+		Error:       result.Error,
+	}
+
+	if result.Exemption != nil {
+		reportLine.ExemptionReason = result.Exemption.Reason
+		reportLine.ExemptionApprovedBy = result.Exemption.ApprovedBy
+		reportLine.ExemptionExpires = result.Exemption.Expires.Format(time.RFC3339)
+	}
+
+	report.Lines = append(report.Lines, reportLine)
 
-	switch status {
+	switch result.Status {
 	case types.RuleStatusIgnore:
 		report.Summary.Ignore++
 	case types.RuleStatusDeny:
 		report.Summary.Deny++
 	case types.RuleStatusAllow:
 		report.Summary.Allow++
+	case types.RuleStatusExempt:
+		report.Summary.Exempt++
 	}
 }
 
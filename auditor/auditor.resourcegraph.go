@@ -21,12 +21,12 @@ func (auditor *AzureAuditor) auditResourceGraph(ctx context.Context, logger *zap
 	violationMetric := prometheusCommon.NewMetricsList()
 
 	for _, object := range list {
-		matchingRuleId, status := config.Validate(object)
-		report.Add(object, matchingRuleId, status)
+		result := config.Validate(object)
+		report.Add(object, result)
 
-		if status.IsDeny() && config.IsMetricsEnabled() {
+		if result.Status.IsDeny() && config.IsMetricsEnabled() {
 			violationMetric.AddInfo(
-				config.CreatePrometheusMetricFromAzureObject(object, matchingRuleId),
+				config.CreatePrometheusMetricFromAzureObject(object, result),
 			)
 		}
 	}
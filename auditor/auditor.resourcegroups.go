@@ -18,12 +18,12 @@ func (auditor *AzureAuditor) auditResourceGroups(ctx context.Context, logger *za
 	violationMetric := prometheusCommon.NewMetricsList()
 
 	for _, object := range list {
-		matchingRuleId, status := auditor.config.ResourceGroups.Validate(object)
-		report.Add(object, matchingRuleId, status)
+		result := auditor.config.ResourceGroups.Validate(object)
+		report.Add(object, result)
 
-		if status.IsDeny() && auditor.config.ResourceGroups.IsMetricsEnabled() {
+		if result.Status.IsDeny() && auditor.config.ResourceGroups.IsMetricsEnabled() {
 			violationMetric.AddInfo(
-				auditor.config.ResourceGroups.CreatePrometheusMetricFromAzureObject(object, matchingRuleId),
+				auditor.config.ResourceGroups.CreatePrometheusMetricFromAzureObject(object, result),
 			)
 		}
 	}
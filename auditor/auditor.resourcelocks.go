@@ -0,0 +1,132 @@
+package auditor
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armlocks"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditResourceLocks(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchResourceLocks(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.ResourceLocks.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.ResourceLocks.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.ResourceLocks.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal ResourceLocks", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.resourceLock)
+	}
+}
+
+func (auditor *AzureAuditor) fetchResourceLocks(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	client, err := armlocks.NewManagementLocksClient(to.String(subscription.SubscriptionID), auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	subscriptionScope := strings.ToLower(to.String(subscription.ID))
+	lockedScopes := map[string]bool{}
+
+	pager := client.NewListAtSubscriptionLevelPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			// incomplete lock list would report locked resourcegroups as missing locks, skip subscription
+			logger.Warnf("unable to list ResourceLocks, skipping subscription: %v", err)
+			return []*validator.AzureObject{}
+		}
+
+		for _, lock := range result.Value {
+			if lock.Properties == nil {
+				continue
+			}
+
+			lockId := stringPtrToStringLower(lock.ID)
+
+			// lock id is <scope>/providers/microsoft.authorization/locks/<name>
+			scopeResourceId := lockId
+			if pos := strings.LastIndex(lockId, "/providers/microsoft.authorization/locks/"); pos >= 0 {
+				scopeResourceId = lockId[:pos]
+			}
+			azureScope, scopeType := parseAuthorizationScope(scopeResourceId)
+			lockedScopes[scopeResourceId] = true
+
+			obj := map[string]interface{}{
+				"resource.id":        lockId,
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"resourcegroup.name": azureScope.ResourceGroup,
+
+				"lock.name":      to.String(lock.Name),
+				"lock.level":     to.String((*string)(lock.Properties.Level)),
+				"lock.notes":     to.String(lock.Properties.Notes),
+				"lock.scope":     scopeResourceId,
+				"lock.scopetype": scopeType,
+				"lock.present":   "true",
+			}
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	// synthesize missing locks for production resourcegroups (subscription locks are inherited)
+	if !lockedScopes[subscriptionScope] {
+		for _, resourceGroup := range auditor.getResourceGroupList(ctx, subscription) {
+			resourceGroupId := stringPtrToStringLower(resourceGroup.ID)
+			if lockedScopes[resourceGroupId] || !auditor.isProductionResourceGroup(resourceGroup.Tags) {
+				continue
+			}
+
+			obj := map[string]interface{}{
+				"resource.id":        resourceGroupId,
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"resourcegroup.name": stringPtrToStringLower(resourceGroup.Name),
+
+				"lock.name":      "",
+				"lock.level":     "",
+				"lock.notes":     "",
+				"lock.scope":     resourceGroupId,
+				"lock.scopetype": "resourcegroup",
+				"lock.present":   "false",
+			}
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// isProductionResourceGroup checks if the resourcegroup tags are matching one of the production tags (name=value)
+func (auditor *AzureAuditor) isProductionResourceGroup(tags map[string]*string) bool {
+	for _, productionTag := range auditor.Opts.ResourceLocks.ProductionTags {
+		tagName, tagValue, _ := strings.Cut(productionTag, "=")
+		for name, value := range tags {
+			if strings.EqualFold(name, tagName) && strings.EqualFold(to.String(value), tagValue) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -19,12 +19,12 @@ func (auditor *AzureAuditor) auditResourceProviderFeatures(ctx context.Context,
 	violationMetric := prometheusCommon.NewMetricsList()
 
 	for _, object := range list {
-		matchingRuleId, status := auditor.config.ResourceProviderFeatures.Validate(object)
-		report.Add(object, matchingRuleId, status)
+		result := auditor.config.ResourceProviderFeatures.Validate(object)
+		report.Add(object, result)
 
-		if status.IsDeny() && auditor.config.ResourceProviderFeatures.IsMetricsEnabled() {
+		if result.Status.IsDeny() && auditor.config.ResourceProviderFeatures.IsMetricsEnabled() {
 			violationMetric.AddInfo(
-				auditor.config.ResourceProviderFeatures.CreatePrometheusMetricFromAzureObject(object, matchingRuleId),
+				auditor.config.ResourceProviderFeatures.CreatePrometheusMetricFromAzureObject(object, result),
 			)
 		}
 	}
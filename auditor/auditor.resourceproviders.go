@@ -20,12 +20,12 @@ func (auditor *AzureAuditor) auditResourceProviders(ctx context.Context, logger
 	violationMetric := prometheusCommon.NewMetricsList()
 
 	for _, object := range list {
-		matchingRuleId, status := auditor.config.ResourceProviders.Validate(object)
-		report.Add(object, matchingRuleId, status)
+		result := auditor.config.ResourceProviders.Validate(object)
+		report.Add(object, result)
 
-		if status.IsDeny() && auditor.config.ResourceProviders.IsMetricsEnabled() {
+		if result.Status.IsDeny() && auditor.config.ResourceProviders.IsMetricsEnabled() {
 			violationMetric.AddInfo(
-				auditor.config.ResourceProviders.CreatePrometheusMetricFromAzureObject(object, matchingRuleId),
+				auditor.config.ResourceProviders.CreatePrometheusMetricFromAzureObject(object, result),
 			)
 		}
 	}
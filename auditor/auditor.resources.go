@@ -0,0 +1,92 @@
+package auditor
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditResources(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchResources(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.Resources.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.Resources.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.Resources.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal Resources", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.resource)
+	}
+}
+
+func (auditor *AzureAuditor) fetchResources(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	for resourceID, resource := range auditor.getResourceList(ctx, subscription) {
+		azureResource, _ := azureCommon.ParseResourceId(resourceID)
+
+		obj := map[string]interface{}{
+			"resource.id":        resourceID,
+			"subscription.id":    to.String(subscription.SubscriptionID),
+			"resourcegroup.name": azureResource.ResourceGroup,
+
+			"resource.kind":              stringPtrToStringLower(resource.Kind),
+			"resource.managedby":         stringPtrToStringLower(resource.ManagedBy),
+			"resource.provisioningstate": stringPtrToStringLower(resource.ProvisioningState),
+			"resource.sku.name":          "",
+			"resource.sku.tier":          "",
+			"resource.identity.type":     "",
+		}
+
+		if resource.SKU != nil {
+			obj["resource.sku.name"] = stringPtrToStringLower(resource.SKU.Name)
+			obj["resource.sku.tier"] = stringPtrToStringLower(resource.SKU.Tier)
+		}
+
+		if resource.Identity != nil && resource.Identity.Type != nil {
+			obj["resource.identity.type"] = strings.ToLower(string(*resource.Identity.Type))
+		}
+
+		list = append(list, validator.NewAzureObject(obj))
+	}
+
+	// location and tags (including inheritance) are added by enrichment
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	// list of all (non empty) tag names, eg. to detect missing tags
+	for _, row := range list {
+		obj := *row
+
+		tagList := []string{}
+		for key, val := range obj {
+			if tagName, found := strings.CutPrefix(key, "resource.tag."); found {
+				if tagValue, ok := val.(string); ok && tagValue != "" {
+					tagList = append(tagList, strings.ToLower(tagName))
+				}
+			}
+		}
+		sort.Strings(tagList)
+
+		obj["resource.tags"] = tagList
+	}
+
+	return
+}
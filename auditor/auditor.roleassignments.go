@@ -6,6 +6,7 @@ import (
 	"time"
 
 	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 	prometheusCommon "github.com/webdevops/go-common/prometheus"
 	"github.com/webdevops/go-common/utils/to"
@@ -22,12 +23,12 @@ func (auditor *AzureAuditor) auditRoleAssignments(ctx context.Context, logger *z
 	violationMetric := prometheusCommon.NewMetricsList()
 
 	for _, object := range list {
-		matchingRuleId, status := auditor.config.RoleAssignments.Validate(object)
-		report.Add(object, matchingRuleId, status)
+		result := auditor.config.RoleAssignments.Validate(object)
+		report.Add(object, result)
 
-		if status.IsDeny() && auditor.config.RoleAssignments.IsMetricsEnabled() {
+		if result.Status.IsDeny() && auditor.config.RoleAssignments.IsMetricsEnabled() {
 			violationMetric.AddInfo(
-				auditor.config.RoleAssignments.CreatePrometheusMetricFromAzureObject(object, matchingRuleId),
+				auditor.config.RoleAssignments.CreatePrometheusMetricFromAzureObject(object, result),
 			)
 		}
 	}
@@ -46,6 +47,15 @@ func (auditor *AzureAuditor) fetchRoleAssignments(ctx context.Context, logger *z
 		logger.Panic(err)
 	}
 
+	// management group scoped assignments are audited by their own report if enabled
+	skipManagementGroupScope := auditor.isManagementGroupRoleAssignmentAuditEnabled()
+
+	// privileged identity management (eligible and time bound assignments)
+	var roleAssignmentScheduleInstanceList *roleAssignmentScheduleInstanceIndex
+	if auditor.Opts.RoleAssignments.Pim {
+		roleAssignmentScheduleInstanceList = auditor.fetchRoleAssignmentScheduleInstances(ctx, logger, *subscription.ID, nil)
+	}
+
 	pager := client.NewListForSubscriptionPager(nil)
 	for pager.More() {
 		result, err := pager.NextPage(ctx)
@@ -54,38 +64,41 @@ func (auditor *AzureAuditor) fetchRoleAssignments(ctx context.Context, logger *z
 		}
 
 		for _, roleAssignment := range result.Value {
-			scopeResourceId := strings.ToLower(to.String(roleAssignment.Properties.Scope))
-
-			azureScope, _ := azureCommon.ParseResourceId(scopeResourceId)
-
-			scopeType := ""
-			if azureScope.ResourceName != "" {
-				scopeType = "resource"
-			} else if azureScope.ResourceGroup != "" {
-				scopeType = "resourcegroup"
-			} else if azureScope.Subscription != "" {
-				scopeType = "subscription"
-			} else if strings.HasPrefix(scopeResourceId, "/providers/microsoft.management/managementgroups/") {
-				scopeType = "managementgroup"
+			obj := newRoleAssignmentAzureObjectData(roleAssignment)
+
+			if skipManagementGroupScope {
+				switch obj["roleassignment.scopetype"] {
+				case "managementgroup", "root":
+					// audited once by management group report
+					continue
+				}
 			}
 
-			obj := map[string]interface{}{
-				"resource.id":        stringPtrToStringLower(roleAssignment.ID),
-				"subscription.id":    to.String(subscription.SubscriptionID),
-				"roledefinition.id":  stringPtrToStringLower(roleAssignment.Properties.RoleDefinitionID),
-				"principal.objectid": stringPtrToStringLower(roleAssignment.Properties.PrincipalID),
-				"resourcegroup.name": azureScope.ResourceGroup,
-
-				"roleassignment.type":        stringPtrToStringLower(roleAssignment.Type),
-				"roleassignment.description": to.String(roleAssignment.Properties.Description),
-				"roleassignment.scope":       stringPtrToStringLower(roleAssignment.Properties.Scope),
-				"roleassignment.scopetype":   scopeType,
-				"roleassignment.createdon":   *roleAssignment.Properties.CreatedOn,
-				"roleassignment.age":         time.Since(*roleAssignment.Properties.CreatedOn),
+			if auditor.Opts.RoleAssignments.Pim {
+				applyRoleAssignmentScheduleInstance(obj, roleAssignmentScheduleInstanceList)
 			}
 
+			obj["subscription.id"] = to.String(subscription.SubscriptionID)
+
 			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	if auditor.Opts.RoleAssignments.Pim {
+		for _, obj := range auditor.fetchRoleEligibilityScheduleInstances(ctx, logger, *subscription.ID, nil) {
+			applyRoleEligibilityActivation(obj, roleAssignmentScheduleInstanceList)
+
+			if skipManagementGroupScope {
+				switch obj["roleassignment.scopetype"] {
+				case "managementgroup", "root":
+					// audited once by management group report
+					continue
+				}
+			}
 
+			obj["subscription.id"] = to.String(subscription.SubscriptionID)
+
+			list = append(list, validator.NewAzureObject(obj))
 		}
 	}
 
@@ -93,3 +106,168 @@ func (auditor *AzureAuditor) fetchRoleAssignments(ctx context.Context, logger *z
 
 	return
 }
+
+func (auditor *AzureAuditor) auditRoleAssignmentsByManagementGroup(ctx context.Context, logger *zap.SugaredLogger, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchRoleAssignmentsByManagementGroup(ctx, logger)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.RoleAssignments.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.RoleAssignments.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.RoleAssignments.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal ManagementGroup RoleAssignments", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.roleAssignmentMgmtGroup)
+	}
+}
+
+func (auditor *AzureAuditor) fetchRoleAssignmentsByManagementGroup(ctx context.Context, logger *zap.SugaredLogger) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	// subscription is not needed for scope based listing
+	client, err := armauthorization.NewRoleAssignmentsClient("", auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	for managementGroupID, managementGroup := range auditor.getManagementGroupList(ctx) {
+		if managementGroup.Properties == nil {
+			logger.Warnf("unable to audit ManagementGroup \"%v\": no properties returned", managementGroupID)
+			continue
+		}
+
+		managementGroupList := []*validator.AzureObject{}
+		isRootManagementGroup := managementGroup.Properties.Details == nil || managementGroup.Properties.Details.Parent == nil || managementGroup.Properties.Details.Parent.ID == nil
+
+		var roleAssignmentScheduleInstanceList *roleAssignmentScheduleInstanceIndex
+		if auditor.Opts.RoleAssignments.Pim {
+			roleAssignmentScheduleInstanceList = auditor.fetchRoleAssignmentScheduleInstances(ctx, logger, managementGroupID, to.StringPtr("atScope()"))
+		}
+
+		// atScope() returns assignments at and above the management group, only the ones
+		// directly assigned on the management group (or root scope for the root management group) are used
+		pager := client.NewListForScopePager(managementGroupID, &armauthorization.RoleAssignmentsClientListForScopeOptions{
+			Filter: to.StringPtr("atScope()"),
+		})
+		for pager.More() {
+			result, err := pager.NextPage(ctx)
+			if err != nil {
+				logger.Panic(err)
+			}
+
+			for _, roleAssignment := range result.Value {
+				obj := newRoleAssignmentAzureObjectData(roleAssignment)
+
+				scope := obj["roleassignment.scope"].(string)
+				if scope != managementGroupID && !(scope == "/" && isRootManagementGroup) {
+					continue
+				}
+
+				if auditor.Opts.RoleAssignments.Pim {
+					applyRoleAssignmentScheduleInstance(obj, roleAssignmentScheduleInstanceList)
+				}
+
+				applyManagementGroupInfo(obj, managementGroupID, managementGroup)
+
+				managementGroupList = append(managementGroupList, validator.NewAzureObject(obj))
+			}
+		}
+
+		if auditor.Opts.RoleAssignments.Pim {
+			for _, obj := range auditor.fetchRoleEligibilityScheduleInstances(ctx, logger, managementGroupID, to.StringPtr("atScope()")) {
+				applyRoleEligibilityActivation(obj, roleAssignmentScheduleInstanceList)
+
+				scope := obj["roleassignment.scope"].(string)
+				if scope != managementGroupID && !(scope == "/" && isRootManagementGroup) {
+					continue
+				}
+
+				applyManagementGroupInfo(obj, managementGroupID, managementGroup)
+
+				managementGroupList = append(managementGroupList, validator.NewAzureObject(obj))
+			}
+		}
+
+		auditor.enrichAzureObjectsWithRoleDefinitionsByScope(ctx, managementGroupID, &managementGroupList)
+		list = append(list, managementGroupList...)
+	}
+
+	auditor.enrichAzureObjects(ctx, nil, &list)
+
+	return
+}
+
+func newRoleAssignmentAzureObjectData(roleAssignment *armauthorization.RoleAssignment) map[string]interface{} {
+	scopeResourceId := strings.ToLower(to.String(roleAssignment.Properties.Scope))
+	azureScope, scopeType := parseAuthorizationScope(scopeResourceId)
+
+	return map[string]interface{}{
+		"resource.id":        stringPtrToStringLower(roleAssignment.ID),
+		"roledefinition.id":  stringPtrToStringLower(roleAssignment.Properties.RoleDefinitionID),
+		"principal.objectid": stringPtrToStringLower(roleAssignment.Properties.PrincipalID),
+		"resourcegroup.name": azureScope.ResourceGroup,
+
+		"roleassignment.type":           stringPtrToStringLower(roleAssignment.Type),
+		"roleassignment.description":    to.String(roleAssignment.Properties.Description),
+		"roleassignment.scope":          scopeResourceId,
+		"roleassignment.scopetype":      scopeType,
+		"roleassignment.assignmenttype": "active",
+		"roleassignment.createdon":      *roleAssignment.Properties.CreatedOn,
+		"roleassignment.age":            time.Since(*roleAssignment.Properties.CreatedOn),
+	}
+}
+
+// parseAuthorizationScope parses the scope of role and deny assignments and detects the scope type
+func parseAuthorizationScope(scopeResourceId string) (azureScope *azureCommon.AzureResourceDetails, scopeType string) {
+	azureScope, _ = azureCommon.ParseResourceId(scopeResourceId)
+
+	if azureScope.ResourceName != "" {
+		scopeType = "resource"
+	} else if azureScope.ResourceGroup != "" {
+		scopeType = "resourcegroup"
+	} else if azureScope.Subscription != "" {
+		scopeType = "subscription"
+	} else if strings.HasPrefix(scopeResourceId, "/providers/microsoft.management/managementgroups/") {
+		scopeType = "managementgroup"
+	} else if scopeResourceId == "/" {
+		scopeType = "root"
+	}
+
+	return
+}
+
+func applyManagementGroupInfo(obj map[string]interface{}, managementGroupID string, managementGroup *armmanagementgroups.ManagementGroup) {
+	obj["managementgroup.id"] = managementGroupID
+	obj["managementgroup.name"] = stringPtrToStringLower(managementGroup.Name)
+	obj["managementgroup.displayname"] = ""
+	if managementGroup.Properties != nil {
+		obj["managementgroup.displayname"] = to.String(managementGroup.Properties.DisplayName)
+	}
+	obj["managementgroup.path"] = managementGroupPath(managementGroup)
+}
+
+// managementGroupPath returns the management group hierarchy (from root) as lowercased path
+func managementGroupPath(managementGroup *armmanagementgroups.ManagementGroup) string {
+	managementGroupName := stringPtrToStringLower(managementGroup.Name)
+
+	pathList := []string{}
+	if managementGroup.Properties != nil && managementGroup.Properties.Details != nil {
+		for _, pathElement := range managementGroup.Properties.Details.Path {
+			pathList = append(pathList, stringPtrToStringLower(pathElement.Name))
+		}
+	}
+
+	if len(pathList) == 0 || pathList[len(pathList)-1] != managementGroupName {
+		pathList = append(pathList, managementGroupName)
+	}
+
+	return "/" + strings.Join(pathList, "/")
+}
@@ -0,0 +1,33 @@
+package auditor
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
+	"github.com/webdevops/go-common/utils/to"
+)
+
+func TestManagementGroupPath(t *testing.T) {
+	managementGroup := &armmanagementgroups.ManagementGroup{
+		Name: to.StringPtr("Platform"),
+		Properties: &armmanagementgroups.ManagementGroupProperties{
+			Details: &armmanagementgroups.ManagementGroupDetails{
+				Path: []*armmanagementgroups.ManagementGroupPathElement{
+					{Name: to.StringPtr("Tenant-Root")},
+					{Name: to.StringPtr("Contoso")},
+				},
+			},
+		},
+	}
+
+	// same convention as subscription.managementgroup.path (lowercased, from root)
+	if result := managementGroupPath(managementGroup); result != "/tenant-root/contoso/platform" {
+		t.Errorf("expected management group path /tenant-root/contoso/platform, got %v", result)
+	}
+
+	obj := map[string]interface{}{}
+	applyManagementGroupInfo(obj, "/providers/microsoft.management/managementgroups/platform", managementGroup)
+	if obj["managementgroup.name"] != "platform" {
+		t.Errorf("expected lowercased management group name platform, got %v", obj["managementgroup.name"])
+	}
+}
@@ -0,0 +1,315 @@
+package auditor
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+const (
+	RoleDefinitionTypeCustom = "customrole"
+
+	RoleAssignmentWriteAction = "microsoft.authorization/roleassignments/write"
+)
+
+func (auditor *AzureAuditor) auditRoleDefinitions(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchRoleDefinitions(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.RoleDefinitions.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.RoleDefinitions.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.RoleDefinitions.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal RoleDefinitions", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.roleDefinition)
+	}
+}
+
+func (auditor *AzureAuditor) fetchRoleDefinitions(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	roleDefinitionList := auditor.getRoleDefinitionList(ctx, subscription)
+	assignmentCountList := auditor.fetchRoleDefinitionAssignmentCount(ctx, logger, subscription, roleDefinitionList)
+
+	for roleDefinitionId, roleDefinition := range roleDefinitionList {
+		if roleDefinition.Properties == nil || !strings.EqualFold(to.String(roleDefinition.Properties.RoleType), RoleDefinitionTypeCustom) {
+			// only custom roles are audited, builtin roles are managed by Microsoft
+			continue
+		}
+
+		actionList := []string{}
+		notActionList := []string{}
+		dataActionList := []string{}
+		notDataActionList := []string{}
+		for _, permission := range roleDefinition.Properties.Permissions {
+			actionList = append(actionList, to.Slice(permission.Actions)...)
+			notActionList = append(notActionList, to.Slice(permission.NotActions)...)
+			dataActionList = append(dataActionList, to.Slice(permission.DataActions)...)
+			notDataActionList = append(notDataActionList, to.Slice(permission.NotDataActions)...)
+		}
+
+		assignableScopeList := []string{}
+		for _, scope := range roleDefinition.Properties.AssignableScopes {
+			assignableScopeList = append(assignableScopeList, stringPtrToStringLower(scope))
+		}
+
+		if !roleDefinitionIsSubscriptionScoped(to.String(subscription.ID), assignableScopeList) {
+			// role is visible in every subscription it is assignable to (eg. below a management group), audit it only once per run
+			if _, audited := auditor.roleDefinitionsAudited.LoadOrStore(strings.ToLower(path.Base(roleDefinitionId)), true); audited {
+				continue
+			}
+		}
+
+		wildcardActionList := roleDefinitionWildcardActionList(append(actionList, dataActionList...))
+		assignmentCount := assignmentCountList[strings.ToLower(path.Base(roleDefinitionId))]
+
+		obj := map[string]interface{}{
+			"resource.id":       roleDefinitionId,
+			"subscription.id":   to.String(subscription.SubscriptionID),
+			"roledefinition.id": roleDefinitionId,
+
+			"roledefinition.name":             to.String(roleDefinition.Properties.RoleName),
+			"roledefinition.type":             to.String(roleDefinition.Properties.RoleType),
+			"roledefinition.description":      to.String(roleDefinition.Properties.Description),
+			"roledefinition.actions":          actionList,
+			"roledefinition.notactions":       notActionList,
+			"roledefinition.dataactions":      dataActionList,
+			"roledefinition.notdataactions":   notDataActionList,
+			"roledefinition.assignablescopes": assignableScopeList,
+			"roledefinition.wildcardactions":  wildcardActionList,
+			"roledefinition.haswildcard":      strconv.FormatBool(len(wildcardActionList) > 0),
+			"roledefinition.ownerequivalent":  strconv.FormatBool(roleDefinitionIsOwnerEquivalent(actionList, notActionList)),
+			"roledefinition.assignmentcount":  assignmentCount,
+			"roledefinition.assigned":         strconv.FormatBool(assignmentCount > 0),
+		}
+
+		list = append(list, validator.NewAzureObject(obj))
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// roleDefinitionIsSubscriptionScoped returns true if all assignable scopes of the role are within the subscription
+func roleDefinitionIsSubscriptionScoped(subscriptionScope string, assignableScopeList []string) bool {
+	subscriptionScope = strings.TrimSuffix(strings.ToLower(subscriptionScope), "/")
+
+	for _, scope := range assignableScopeList {
+		scope = strings.ToLower(scope)
+		if scope != subscriptionScope && !strings.HasPrefix(scope, subscriptionScope+"/") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fetchRoleDefinitionAssignmentCount returns the number of (active and if enabled eligible) role assignments
+// visible in the subscription and below the management groups the custom roles are assignable to,
+// indexed by the role definition name (guid)
+func (auditor *AzureAuditor) fetchRoleDefinitionAssignmentCount(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, roleDefinitionList map[string]*armauthorization.RoleDefinition) map[string]int64 {
+	// role definition name (guid) indexed by role assignment id, assignments are visible from multiple scopes
+	assignmentList := map[string]string{}
+
+	client, err := armauthorization.NewRoleAssignmentsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListForSubscriptionPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, roleAssignment := range result.Value {
+			if roleAssignment.Properties == nil {
+				continue
+			}
+
+			assignmentList[stringPtrToStringLower(roleAssignment.ID)] = strings.ToLower(path.Base(to.String(roleAssignment.Properties.RoleDefinitionID)))
+		}
+	}
+
+	if auditor.Opts.RoleAssignments.Pim {
+		for _, obj := range auditor.fetchRoleEligibilityScheduleInstances(ctx, logger, *subscription.ID, nil) {
+			if roleDefinitionId, ok := obj["roledefinition.id"].(string); ok {
+				assignmentList[obj["resource.id"].(string)] = path.Base(roleDefinitionId)
+			}
+		}
+	}
+
+	// custom roles assignable to management groups can be assigned outside of the subscription
+	for _, scope := range roleDefinitionManagementGroupScopeList(roleDefinitionList) {
+		for roleAssignmentId, roleDefinitionName := range auditor.getRoleAssignmentRoleDefinitionListByScope(ctx, logger, scope) {
+			assignmentList[roleAssignmentId] = roleDefinitionName
+		}
+	}
+
+	return countRoleDefinitionAssignments(assignmentList)
+}
+
+// getRoleAssignmentRoleDefinitionListByScope returns the role definition name (guid) of all (active and if enabled eligible)
+// role assignments at, above and below the scope, indexed by the role assignment id
+func (auditor *AzureAuditor) getRoleAssignmentRoleDefinitionListByScope(ctx context.Context, logger *zap.SugaredLogger, scope string) (list map[string]string) {
+	list = map[string]string{}
+
+	cacheKey := "roleassignments:roledefinitions:" + scope
+	if val, ok := auditor.cache.Get(cacheKey); ok {
+		// fetched from cache
+		list = val.(map[string]string)
+		return
+	}
+
+	// subscription is not needed for scope based listing
+	client, err := armauthorization.NewRoleAssignmentsClient("", auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListForScopePager(scope, nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			// management group read access might not be granted, assignments outside the subscription are unknown
+			logger.Warnf("unable to list RoleAssignments for scope %v: %v", scope, err)
+			return map[string]string{}
+		}
+
+		for _, roleAssignment := range result.Value {
+			if roleAssignment.Properties == nil {
+				continue
+			}
+
+			list[stringPtrToStringLower(roleAssignment.ID)] = strings.ToLower(path.Base(to.String(roleAssignment.Properties.RoleDefinitionID)))
+		}
+	}
+
+	if auditor.Opts.RoleAssignments.Pim {
+		for _, obj := range auditor.fetchRoleEligibilityScheduleInstances(ctx, logger, scope, nil) {
+			if roleDefinitionId, ok := obj["roledefinition.id"].(string); ok {
+				list[obj["resource.id"].(string)] = path.Base(roleDefinitionId)
+			}
+		}
+	}
+
+	// save to cache
+	_ = auditor.cache.Add(cacheKey, list, auditor.cacheExpiry)
+
+	return
+}
+
+// roleDefinitionManagementGroupScopeList returns the management group scopes the custom roles are assignable to
+func roleDefinitionManagementGroupScopeList(roleDefinitionList map[string]*armauthorization.RoleDefinition) (list []string) {
+	list = []string{}
+	scopeList := map[string]bool{}
+	for _, roleDefinition := range roleDefinitionList {
+		if roleDefinition.Properties == nil || !strings.EqualFold(to.String(roleDefinition.Properties.RoleType), RoleDefinitionTypeCustom) {
+			continue
+		}
+
+		for _, scope := range roleDefinition.Properties.AssignableScopes {
+			scope := stringPtrToStringLower(scope)
+			if strings.HasPrefix(scope, "/providers/microsoft.management/managementgroups/") && !scopeList[scope] {
+				scopeList[scope] = true
+				list = append(list, scope)
+			}
+		}
+	}
+
+	return
+}
+
+// countRoleDefinitionAssignments returns the number of role assignments per role definition name (guid)
+func countRoleDefinitionAssignments(assignmentList map[string]string) (list map[string]int64) {
+	list = map[string]int64{}
+	for _, roleDefinitionName := range assignmentList {
+		list[roleDefinitionName]++
+	}
+	return
+}
+
+// roleDefinitionWildcardActionList returns all actions containing wildcards (eg. "*", "*/write" or "microsoft.compute/*")
+func roleDefinitionWildcardActionList(actionList []string) (list []string) {
+	list = []string{}
+	for _, action := range actionList {
+		if strings.Contains(action, "*") {
+			list = append(list, strings.ToLower(action))
+		}
+	}
+	return
+}
+
+// roleDefinitionIsOwnerEquivalent detects custom roles which are able to manage role assignments (and therefore can grant
+// themselves any permission like Owner), role assignment management must be granted by actions and not excluded by notActions
+func roleDefinitionIsOwnerEquivalent(actionList, notActionList []string) bool {
+	granted := false
+	for _, action := range actionList {
+		if roleDefinitionActionIsMatching(action, RoleAssignmentWriteAction) {
+			granted = true
+			break
+		}
+	}
+
+	if !granted {
+		return false
+	}
+
+	for _, notAction := range notActionList {
+		if roleDefinitionActionIsMatching(notAction, RoleAssignmentWriteAction) {
+			// role assignment management is excluded, like Contributor
+			return false
+		}
+	}
+
+	return true
+}
+
+// roleDefinitionActionIsMatching checks if the action (which might contain wildcards, eg. "*", "*/write" or "microsoft.authorization/*")
+// is matching the operation, actions are case insensitive
+func roleDefinitionActionIsMatching(action, operation string) bool {
+	action = strings.ToLower(strings.TrimSpace(action))
+	operation = strings.ToLower(operation)
+
+	partList := strings.Split(action, "*")
+	if len(partList) == 1 {
+		return action == operation
+	}
+
+	// first part must be the prefix, last part must be the suffix, parts in between must follow in order
+	if !strings.HasPrefix(operation, partList[0]) {
+		return false
+	}
+	operation = operation[len(partList[0]):]
+
+	lastPart := partList[len(partList)-1]
+	for _, part := range partList[1 : len(partList)-1] {
+		pos := strings.Index(operation, part)
+		if pos < 0 {
+			return false
+		}
+		operation = operation[pos+len(part):]
+	}
+
+	return len(operation) >= len(lastPart) && strings.HasSuffix(operation, lastPart)
+}
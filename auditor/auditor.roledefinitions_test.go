@@ -0,0 +1,165 @@
+package auditor
+
+import (
+	"testing"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/webdevops/go-common/utils/to"
+)
+
+func TestRoleDefinitionActionIsMatching(t *testing.T) {
+	testCases := []struct {
+		action   string
+		expected bool
+	}{
+		{"*", true},
+		{"*/write", true},
+		{"*/read", false},
+		{"Microsoft.Authorization/*", true},
+		{"Microsoft.Authorization/roleAssignments/*", true},
+		{"Microsoft.Authorization/roleAssignments/write", true},
+		{"Microsoft.Authorization/*/write", true},
+		{"Microsoft.Authorization/roleAssignments/read", false},
+		{"Microsoft.Authorization/roleDefinitions/*", false},
+		{"Microsoft.Authorization/*/read", false},
+		{"Microsoft.Compute/*", false},
+		{"Microsoft.Authorization/roleAssignments/write/*", false},
+		{"", false},
+	}
+
+	for _, testCase := range testCases {
+		if result := roleDefinitionActionIsMatching(testCase.action, RoleAssignmentWriteAction); result != testCase.expected {
+			t.Errorf("action \"%v\": expected %v, got %v", testCase.action, testCase.expected, result)
+		}
+	}
+}
+
+func TestRoleDefinitionIsOwnerEquivalent(t *testing.T) {
+	testCases := []struct {
+		name          string
+		actionList    []string
+		notActionList []string
+		expected      bool
+	}{
+		{
+			name:       "owner",
+			actionList: []string{"*"},
+			expected:   true,
+		},
+		{
+			name:          "contributor",
+			actionList:    []string{"*"},
+			notActionList: []string{"Microsoft.Authorization/*/Delete", "Microsoft.Authorization/*/Write", "Microsoft.Authorization/elevateAccess/Action"},
+			expected:      false,
+		},
+		{
+			name:          "wildcard without role assignment management",
+			actionList:    []string{"*"},
+			notActionList: []string{"Microsoft.Authorization/roleAssignments/*"},
+			expected:      false,
+		},
+		{
+			name:          "wildcard without write operations",
+			actionList:    []string{"*"},
+			notActionList: []string{"*/write"},
+			expected:      false,
+		},
+		{
+			name:          "wildcard without everything",
+			actionList:    []string{"*"},
+			notActionList: []string{"*"},
+			expected:      false,
+		},
+		{
+			name:          "wildcard without unrelated authorization actions",
+			actionList:    []string{"*"},
+			notActionList: []string{"Microsoft.Authorization/locks/delete", "Microsoft.Authorization/policyAssignments/*"},
+			expected:      true,
+		},
+		{
+			name:       "user access administrator",
+			actionList: []string{"*/read", "Microsoft.Authorization/*", "Microsoft.Support/*"},
+			expected:   true,
+		},
+		{
+			name:       "role assignment write",
+			actionList: []string{"Microsoft.Compute/*", "Microsoft.Authorization/roleAssignments/write"},
+			expected:   true,
+		},
+		{
+			name:       "role assignment read",
+			actionList: []string{"*/read", "Microsoft.Authorization/roleAssignments/read"},
+			expected:   false,
+		},
+		{
+			name:     "no actions",
+			expected: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		if result := roleDefinitionIsOwnerEquivalent(testCase.actionList, testCase.notActionList); result != testCase.expected {
+			t.Errorf("%v: expected %v, got %v", testCase.name, testCase.expected, result)
+		}
+	}
+}
+
+func TestRoleDefinitionManagementGroupScopeList(t *testing.T) {
+	customRoleType := RoleDefinitionTypeCustom
+	builtinRoleType := "BuiltInRole"
+
+	roleDefinitionList := map[string]*armauthorization.RoleDefinition{
+		"custom1": {
+			Properties: &armauthorization.RoleDefinitionProperties{
+				RoleType: &customRoleType,
+				AssignableScopes: []*string{
+					to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000"),
+					to.StringPtr("/providers/Microsoft.Management/managementGroups/Foo"),
+				},
+			},
+		},
+		"custom2": {
+			Properties: &armauthorization.RoleDefinitionProperties{
+				RoleType: &customRoleType,
+				AssignableScopes: []*string{
+					to.StringPtr("/providers/microsoft.management/managementgroups/foo"),
+				},
+			},
+		},
+		"builtin": {
+			Properties: &armauthorization.RoleDefinitionProperties{
+				RoleType:         &builtinRoleType,
+				AssignableScopes: []*string{to.StringPtr("/providers/microsoft.management/managementgroups/bar")},
+			},
+		},
+		"empty": {},
+	}
+
+	scopeList := roleDefinitionManagementGroupScopeList(roleDefinitionList)
+	if len(scopeList) != 1 || scopeList[0] != "/providers/microsoft.management/managementgroups/foo" {
+		t.Errorf("expected management group scope list [/providers/microsoft.management/managementgroups/foo], got %v", scopeList)
+	}
+}
+
+func TestRoleDefinitionIsSubscriptionScoped(t *testing.T) {
+	subscriptionScope := "/subscriptions/00000000-0000-0000-0000-000000000000"
+
+	testCases := []struct {
+		assignableScopeList []string
+		expected            bool
+	}{
+		{[]string{"/subscriptions/00000000-0000-0000-0000-000000000000"}, true},
+		{[]string{"/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/foo"}, true},
+		{[]string{"/SUBSCRIPTIONS/00000000-0000-0000-0000-000000000000/"}, true},
+		{[]string{"/subscriptions/00000000-0000-0000-0000-000000000000", "/subscriptions/11111111-1111-1111-1111-111111111111"}, false},
+		{[]string{"/subscriptions/00000000-0000-0000-0000-0000000000001"}, false},
+		{[]string{"/providers/microsoft.management/managementgroups/foo"}, false},
+		{[]string{"/"}, false},
+	}
+
+	for _, testCase := range testCases {
+		if result := roleDefinitionIsSubscriptionScoped(subscriptionScope, testCase.assignableScopeList); result != testCase.expected {
+			t.Errorf("assignable scopes %v: expected %v, got %v", testCase.assignableScopeList, testCase.expected, result)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package auditor
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+)
+
+// fetchRoleEligibilityScheduleInstances returns the Privileged Identity Management (PIM) eligible assignments for scope
+func (auditor *AzureAuditor) fetchRoleEligibilityScheduleInstances(ctx context.Context, logger *zap.SugaredLogger, scope string, filter *string) (list []map[string]interface{}) {
+	list = []map[string]interface{}{}
+
+	client, err := armauthorization.NewRoleEligibilityScheduleInstancesClient(auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListForScopePager(scope, &armauthorization.RoleEligibilityScheduleInstancesClientListForScopeOptions{
+		Filter: filter,
+	})
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, eligibility := range result.Value {
+			if eligibility.Properties == nil {
+				continue
+			}
+
+			scopeResourceId := strings.ToLower(to.String(eligibility.Properties.Scope))
+			azureScope, scopeType := parseAuthorizationScope(scopeResourceId)
+
+			obj := map[string]interface{}{
+				"resource.id":        stringPtrToStringLower(eligibility.ID),
+				"roledefinition.id":  stringPtrToStringLower(eligibility.Properties.RoleDefinitionID),
+				"principal.objectid": stringPtrToStringLower(eligibility.Properties.PrincipalID),
+				"resourcegroup.name": azureScope.ResourceGroup,
+
+				"roleassignment.type":           stringPtrToStringLower(eligibility.Type),
+				"roleassignment.description":    "",
+				"roleassignment.scope":          scopeResourceId,
+				"roleassignment.scopetype":      scopeType,
+				"roleassignment.assignmenttype": "eligible",
+				"roleassignment.membertype":     stringPtrToStringLower((*string)(eligibility.Properties.MemberType)),
+
+				"roleassignment.eligibilityscheduleid": stringPtrToStringLower(eligibility.Properties.RoleEligibilityScheduleID),
+			}
+
+			if eligibility.Properties.CreatedOn != nil {
+				obj["roleassignment.createdon"] = *eligibility.Properties.CreatedOn
+				obj["roleassignment.age"] = time.Since(*eligibility.Properties.CreatedOn)
+			}
+
+			applyRoleAssignmentScheduleTimes(obj, eligibility.Properties.StartDateTime, eligibility.Properties.EndDateTime)
+
+			list = append(list, obj)
+		}
+	}
+
+	return
+}
+
+type (
+	// roleAssignmentScheduleInstanceIndex indexes the PIM active assignments by assignment (principal, role and scope)
+	// and by their linked eligibility schedule (activations of eligible assignments)
+	roleAssignmentScheduleInstanceIndex struct {
+		byAssignment          map[string]*armauthorization.RoleAssignmentScheduleInstance
+		byEligibilitySchedule map[string]*armauthorization.RoleAssignmentScheduleInstance
+	}
+)
+
+func newRoleAssignmentScheduleInstanceIndex() *roleAssignmentScheduleInstanceIndex {
+	return &roleAssignmentScheduleInstanceIndex{
+		byAssignment:          map[string]*armauthorization.RoleAssignmentScheduleInstance{},
+		byEligibilitySchedule: map[string]*armauthorization.RoleAssignmentScheduleInstance{},
+	}
+}
+
+func (index *roleAssignmentScheduleInstanceIndex) add(instance *armauthorization.RoleAssignmentScheduleInstance) {
+	if instance == nil || instance.Properties == nil {
+		return
+	}
+
+	// activated eligible assignments are role assignments created by PIM and are not linked
+	// by their role assignment id, so the assignment itself (principal, role and scope) is the key
+	index.byAssignment[roleAssignmentKey(
+		to.String(instance.Properties.PrincipalID),
+		to.String(instance.Properties.RoleDefinitionID),
+		to.String(instance.Properties.Scope),
+	)] = instance
+
+	if linkedScheduleId := stringPtrToStringLower(instance.Properties.LinkedRoleEligibilityScheduleID); linkedScheduleId != "" {
+		index.byEligibilitySchedule[linkedScheduleId] = instance
+	}
+}
+
+// roleAssignmentKey builds the key of an assignment, role definitions are compared by their guid as the id
+// prefix differs between the scopes (eg. subscription vs. management group)
+func roleAssignmentKey(principalId, roleDefinitionId, scope string) string {
+	roleDefinitionId = strings.ToLower(roleDefinitionId)
+	if pos := strings.LastIndex(roleDefinitionId, "/"); pos >= 0 {
+		roleDefinitionId = roleDefinitionId[pos+1:]
+	}
+
+	scope = strings.TrimSuffix(strings.ToLower(scope), "/")
+	if scope == "" {
+		scope = "/"
+	}
+
+	return strings.ToLower(principalId) + "|" + roleDefinitionId + "|" + scope
+}
+
+// fetchRoleAssignmentScheduleInstances returns the Privileged Identity Management (PIM) active assignments for scope
+func (auditor *AzureAuditor) fetchRoleAssignmentScheduleInstances(ctx context.Context, logger *zap.SugaredLogger, scope string, filter *string) (index *roleAssignmentScheduleInstanceIndex) {
+	index = newRoleAssignmentScheduleInstanceIndex()
+
+	client, err := armauthorization.NewRoleAssignmentScheduleInstancesClient(auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListForScopePager(scope, &armauthorization.RoleAssignmentScheduleInstancesClientListForScopeOptions{
+		Filter: filter,
+	})
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, instance := range result.Value {
+			index.add(instance)
+		}
+	}
+
+	return
+}
+
+// applyRoleAssignmentScheduleInstance adds the PIM information of an active role assignment
+func applyRoleAssignmentScheduleInstance(obj map[string]interface{}, index *roleAssignmentScheduleInstanceIndex) {
+	principalId, _ := obj["principal.objectid"].(string)
+	roleDefinitionId, _ := obj["roledefinition.id"].(string)
+	scope, _ := obj["roleassignment.scope"].(string)
+
+	obj["roleassignment.linkedeligibilityscheduleid"] = ""
+	if instance, ok := index.byAssignment[roleAssignmentKey(principalId, roleDefinitionId, scope)]; ok {
+		obj["roleassignment.membertype"] = stringPtrToStringLower((*string)(instance.Properties.MemberType))
+		obj["roleassignment.pimassignmenttype"] = stringPtrToStringLower((*string)(instance.Properties.AssignmentType))
+		obj["roleassignment.linkedeligibilityscheduleid"] = stringPtrToStringLower(instance.Properties.LinkedRoleEligibilityScheduleID)
+		applyRoleAssignmentScheduleTimes(obj, instance.Properties.StartDateTime, instance.Properties.EndDateTime)
+	} else {
+		// not managed by PIM, always permanent
+		obj["roleassignment.membertype"] = "direct"
+		obj["roleassignment.permanent"] = "true"
+	}
+}
+
+// applyRoleEligibilityActivation marks eligible assignments which are currently activated
+func applyRoleEligibilityActivation(obj map[string]interface{}, index *roleAssignmentScheduleInstanceIndex) {
+	eligibilityScheduleId, _ := obj["roleassignment.eligibilityscheduleid"].(string)
+
+	obj["roleassignment.activated"] = "false"
+	if _, ok := index.byEligibilitySchedule[eligibilityScheduleId]; ok && eligibilityScheduleId != "" {
+		obj["roleassignment.activated"] = "true"
+	}
+}
+
+func applyRoleAssignmentScheduleTimes(obj map[string]interface{}, startDateTime, endDateTime *time.Time) {
+	if startDateTime != nil {
+		obj["roleassignment.startdatetime"] = startDateTime.Format(time.RFC3339)
+	}
+
+	if endDateTime != nil {
+		obj["roleassignment.enddatetime"] = endDateTime.Format(time.RFC3339)
+		obj["roleassignment.expiry"] = time.Until(*endDateTime)
+		obj["roleassignment.permanent"] = "false"
+	} else {
+		obj["roleassignment.permanent"] = "true"
+	}
+}
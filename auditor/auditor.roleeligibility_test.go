@@ -0,0 +1,157 @@
+package auditor
+
+import (
+	"testing"
+
+	armauthorization "github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/authorization/armauthorization/v2"
+	"github.com/webdevops/go-common/utils/to"
+)
+
+func newTestRoleAssignmentScheduleInstance(principalId, roleDefinitionId, scope, linkedEligibilityScheduleId string) *armauthorization.RoleAssignmentScheduleInstance {
+	memberType := armauthorization.MemberTypeDirect
+	assignmentType := armauthorization.AssignmentTypeActivated
+
+	instance := &armauthorization.RoleAssignmentScheduleInstance{
+		Properties: &armauthorization.RoleAssignmentScheduleInstanceProperties{
+			// PIM activations create a new role assignment, origin id differs from the listed role assignment
+			OriginRoleAssignmentID: to.StringPtr("/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleAssignments/origin"),
+			PrincipalID:            to.StringPtr(principalId),
+			RoleDefinitionID:       to.StringPtr(roleDefinitionId),
+			Scope:                  to.StringPtr(scope),
+			MemberType:             &memberType,
+			AssignmentType:         &assignmentType,
+		},
+	}
+
+	if linkedEligibilityScheduleId != "" {
+		instance.Properties.LinkedRoleEligibilityScheduleID = to.StringPtr(linkedEligibilityScheduleId)
+	}
+
+	return instance
+}
+
+func TestRoleAssignmentKey(t *testing.T) {
+	testCases := []struct {
+		name                string
+		principalId, roleId string
+		scope               string
+		expectedPrincipalId string
+		expectedRoleId      string
+		expectedScope       string
+	}{
+		{
+			name:                "subscription role definition id",
+			principalId:         "AAAA",
+			roleId:              "/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleDefinitions/8E3AF657-A8FF-443C-A75C-2FE8C4BCB635",
+			scope:               "/subscriptions/00000000-0000-0000-0000-000000000000/",
+			expectedPrincipalId: "aaaa",
+			expectedRoleId:      "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+			expectedScope:       "/subscriptions/00000000-0000-0000-0000-000000000000",
+		},
+		{
+			name:                "tenant role definition id",
+			principalId:         "aaaa",
+			roleId:              "/providers/Microsoft.Authorization/roleDefinitions/8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+			scope:               "/subscriptions/00000000-0000-0000-0000-000000000000",
+			expectedPrincipalId: "aaaa",
+			expectedRoleId:      "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+			expectedScope:       "/subscriptions/00000000-0000-0000-0000-000000000000",
+		},
+		{
+			name:                "root scope",
+			principalId:         "aaaa",
+			roleId:              "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+			scope:               "/",
+			expectedPrincipalId: "aaaa",
+			expectedRoleId:      "8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+			expectedScope:       "/",
+		},
+	}
+
+	for _, testCase := range testCases {
+		key := roleAssignmentKey(testCase.principalId, testCase.roleId, testCase.scope)
+		expectedKey := testCase.expectedPrincipalId + "|" + testCase.expectedRoleId + "|" + testCase.expectedScope
+		if key != expectedKey {
+			t.Errorf("%v: expected key \"%v\", got \"%v\"", testCase.name, expectedKey, key)
+		}
+	}
+}
+
+func TestApplyRoleAssignmentScheduleInstance(t *testing.T) {
+	index := newRoleAssignmentScheduleInstanceIndex()
+	index.add(newTestRoleAssignmentScheduleInstance(
+		"AAAA",
+		"/providers/Microsoft.Authorization/roleDefinitions/8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+		"/subscriptions/00000000-0000-0000-0000-000000000000",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleEligibilitySchedules/SCHEDULE",
+	))
+	index.add(nil)
+	index.add(&armauthorization.RoleAssignmentScheduleInstance{})
+
+	// activated role assignment (listed with its own id and the subscription role definition id)
+	obj := map[string]interface{}{
+		"resource.id":          "/subscriptions/00000000-0000-0000-0000-000000000000/providers/microsoft.authorization/roleassignments/activated",
+		"principal.objectid":   "aaaa",
+		"roledefinition.id":    "/subscriptions/00000000-0000-0000-0000-000000000000/providers/microsoft.authorization/roledefinitions/8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+		"roleassignment.scope": "/subscriptions/00000000-0000-0000-0000-000000000000",
+	}
+	applyRoleAssignmentScheduleInstance(obj, index)
+
+	if obj["roleassignment.pimassignmenttype"] != "activated" {
+		t.Errorf("expected pimassignmenttype \"activated\", got \"%v\"", obj["roleassignment.pimassignmenttype"])
+	}
+
+	if obj["roleassignment.linkedeligibilityscheduleid"] != "/subscriptions/00000000-0000-0000-0000-000000000000/providers/microsoft.authorization/roleeligibilityschedules/schedule" {
+		t.Errorf("unexpected linkedeligibilityscheduleid \"%v\"", obj["roleassignment.linkedeligibilityscheduleid"])
+	}
+
+	// role assignment on another scope is not managed by PIM
+	obj = map[string]interface{}{
+		"principal.objectid":   "aaaa",
+		"roledefinition.id":    "/subscriptions/00000000-0000-0000-0000-000000000000/providers/microsoft.authorization/roledefinitions/8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+		"roleassignment.scope": "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/foobar",
+	}
+	applyRoleAssignmentScheduleInstance(obj, index)
+
+	if obj["roleassignment.membertype"] != "direct" || obj["roleassignment.permanent"] != "true" {
+		t.Errorf("expected direct permanent assignment, got membertype \"%v\" and permanent \"%v\"", obj["roleassignment.membertype"], obj["roleassignment.permanent"])
+	}
+
+	if obj["roleassignment.linkedeligibilityscheduleid"] != "" {
+		t.Errorf("expected empty linkedeligibilityscheduleid, got \"%v\"", obj["roleassignment.linkedeligibilityscheduleid"])
+	}
+}
+
+func TestApplyRoleEligibilityActivation(t *testing.T) {
+	index := newRoleAssignmentScheduleInstanceIndex()
+	index.add(newTestRoleAssignmentScheduleInstance(
+		"aaaa",
+		"8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+		"/subscriptions/00000000-0000-0000-0000-000000000000",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/providers/Microsoft.Authorization/roleEligibilitySchedules/SCHEDULE",
+	))
+	// active assignment without eligibility (eg. time bound assignment)
+	index.add(newTestRoleAssignmentScheduleInstance(
+		"bbbb",
+		"8e3af657-a8ff-443c-a75c-2fe8c4bcb635",
+		"/subscriptions/00000000-0000-0000-0000-000000000000",
+		"",
+	))
+
+	testCases := map[string]string{
+		"/subscriptions/00000000-0000-0000-0000-000000000000/providers/microsoft.authorization/roleeligibilityschedules/schedule": "true",
+		"/subscriptions/00000000-0000-0000-0000-000000000000/providers/microsoft.authorization/roleeligibilityschedules/other":    "false",
+		"": "false",
+	}
+
+	for eligibilityScheduleId, expected := range testCases {
+		obj := map[string]interface{}{
+			"roleassignment.eligibilityscheduleid": eligibilityScheduleId,
+		}
+		applyRoleEligibilityActivation(obj, index)
+
+		if obj["roleassignment.activated"] != expected {
+			t.Errorf("eligibility schedule \"%v\": expected activated \"%v\", got \"%v\"", eligibilityScheduleId, expected, obj["roleassignment.activated"])
+		}
+	}
+}
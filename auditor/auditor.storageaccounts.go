@@ -0,0 +1,117 @@
+package auditor
+
+import (
+	"context"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	azureCommon "github.com/webdevops/go-common/azuresdk/armclient"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+func (auditor *AzureAuditor) auditStorageAccounts(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchStorageAccounts(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.StorageAccounts.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.StorageAccounts.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.StorageAccounts.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal StorageAccounts", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.storageAccount)
+	}
+}
+
+func (auditor *AzureAuditor) fetchStorageAccounts(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	client, err := armstorage.NewAccountsClient(*subscription.SubscriptionID, auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		result, err := pager.NextPage(ctx)
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		for _, storageAccount := range result.Value {
+			if storageAccount.Properties == nil {
+				continue
+			}
+
+			azureResource, _ := azureCommon.ParseResourceId(to.String(storageAccount.ID))
+
+			obj := map[string]interface{}{
+				"resource.id":        stringPtrToStringLower(storageAccount.ID),
+				"subscription.id":    to.String(subscription.SubscriptionID),
+				"resourcegroup.name": azureResource.ResourceGroup,
+
+				"storage.name":                         azureResource.ResourceName,
+				"storage.location":                     stringPtrToStringLower(storageAccount.Location),
+				"storage.kind":                         stringPtrToStringLower((*string)(storageAccount.Kind)),
+				"storage.allowblobpublicaccess":        boolPtrToString(storageAccount.Properties.AllowBlobPublicAccess),
+				"storage.allowsharedkeyaccess":         boolPtrToString(storageAccount.Properties.AllowSharedKeyAccess),
+				"storage.allowcrosstenantreplication":  boolPtrToString(storageAccount.Properties.AllowCrossTenantReplication),
+				"storage.defaulttooauthauthentication": boolPtrToString(storageAccount.Properties.DefaultToOAuthAuthentication),
+				"storage.httpsonly":                    boolPtrToString(storageAccount.Properties.EnableHTTPSTrafficOnly),
+				"storage.minimumtlsversion":            normalizeTlsVersion(to.String((*string)(storageAccount.Properties.MinimumTLSVersion))),
+				"storage.publicnetworkaccess":          stringPtrToStringLower((*string)(storageAccount.Properties.PublicNetworkAccess)),
+				"storage.privateendpointcount":         int64(len(storageAccount.Properties.PrivateEndpointConnections)),
+			}
+
+			if storageAccount.SKU != nil {
+				obj["storage.sku"] = stringPtrToStringLower((*string)(storageAccount.SKU.Name))
+			}
+
+			if storageAccount.Properties.CreationTime != nil {
+				obj["storage.createdon"] = *storageAccount.Properties.CreationTime
+				obj["storage.age"] = time.Since(*storageAccount.Properties.CreationTime)
+			}
+
+			if networkAcls := storageAccount.Properties.NetworkRuleSet; networkAcls != nil {
+				ipRuleList := []string{}
+				for _, ipRule := range networkAcls.IPRules {
+					ipRuleList = append(ipRuleList, to.String(ipRule.IPAddressOrRange))
+				}
+
+				obj["storage.networkacls.defaultaction"] = stringPtrToStringLower((*string)(networkAcls.DefaultAction))
+				obj["storage.networkacls.bypass"] = stringPtrToStringLower((*string)(networkAcls.Bypass))
+				obj["storage.networkacls.iprules"] = ipRuleList
+			}
+
+			if keyCreationTime := storageAccount.Properties.KeyCreationTime; keyCreationTime != nil {
+				if keyCreationTime.Key1 != nil {
+					obj["storage.key1.createdon"] = *keyCreationTime.Key1
+					obj["storage.key1.age"] = time.Since(*keyCreationTime.Key1)
+				}
+
+				if keyCreationTime.Key2 != nil {
+					obj["storage.key2.createdon"] = *keyCreationTime.Key2
+					obj["storage.key2.age"] = time.Since(*keyCreationTime.Key2)
+				}
+			}
+
+			list = append(list, validator.NewAzureObject(obj))
+		}
+	}
+
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
@@ -0,0 +1,159 @@
+package auditor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	prometheusCommon "github.com/webdevops/go-common/prometheus"
+	"github.com/webdevops/go-common/utils/to"
+	"go.uber.org/zap"
+
+	"github.com/webdevops/azure-auditor/auditor/validator"
+)
+
+const (
+	// management group ancestors chain is ordered from the direct parent up to the root management group
+	SubscriptionManagementGroupResourceGraphQuery = `resourcecontainers
+| where type =~ "microsoft.resources/subscriptions"
+| project subscriptionId, managementGroupAncestorsChain = properties.managementGroupAncestorsChain`
+)
+
+func (auditor *AzureAuditor) auditSubscriptions(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription, report *AzureAuditorReport, callback chan<- func()) {
+	list := auditor.fetchSubscriptions(ctx, logger, subscription)
+
+	violationMetric := prometheusCommon.NewMetricsList()
+
+	for _, object := range list {
+		result := auditor.config.Subscriptions.Validate(object)
+		report.Add(object, result)
+
+		if result.Status.IsDeny() && auditor.config.Subscriptions.IsMetricsEnabled() {
+			violationMetric.AddInfo(
+				auditor.config.Subscriptions.CreatePrometheusMetricFromAzureObject(object, result),
+			)
+		}
+	}
+
+	callback <- func() {
+		logger.Infof("found %v illegal Subscriptions", len(violationMetric.GetList()))
+		violationMetric.GaugeSetInc(auditor.prometheus.subscription)
+	}
+}
+
+func (auditor *AzureAuditor) fetchSubscriptions(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []*validator.AzureObject) {
+	list = []*validator.AzureObject{}
+
+	obj := map[string]interface{}{
+		"resource.id":     stringPtrToStringLower(subscription.ID),
+		"subscription.id": to.String(subscription.SubscriptionID),
+
+		"subscription.state":               "",
+		"subscription.tenantid":            stringPtrToStringLower(subscription.TenantID),
+		"subscription.authorizationsource": to.String(subscription.AuthorizationSource),
+		"subscription.quotaid":             "",
+		"subscription.spendinglimit":       "",
+		"subscription.locationplacementid": "",
+	}
+
+	if subscription.State != nil {
+		obj["subscription.state"] = strings.ToLower(string(*subscription.State))
+	}
+
+	if subscription.SubscriptionPolicies != nil {
+		obj["subscription.quotaid"] = to.String(subscription.SubscriptionPolicies.QuotaID)
+		obj["subscription.locationplacementid"] = to.String(subscription.SubscriptionPolicies.LocationPlacementID)
+		if subscription.SubscriptionPolicies.SpendingLimit != nil {
+			obj["subscription.spendinglimit"] = strings.ToLower(string(*subscription.SubscriptionPolicies.SpendingLimit))
+		}
+	}
+
+	tagList := []string{}
+	for tagName, tagValue := range subscription.Tags {
+		if to.String(tagValue) != "" {
+			tagList = append(tagList, strings.ToLower(tagName))
+		}
+	}
+	sort.Strings(tagList)
+	obj["subscription.tags"] = tagList
+
+	// management group ancestry
+	managementGroupList := auditor.fetchSubscriptionManagementGroupAncestors(ctx, logger, subscription)
+	obj["subscription.managementgroups"] = managementGroupList
+	obj["subscription.managementgroup.name"] = ""
+	obj["subscription.managementgroup.path"] = ""
+	if len(managementGroupList) > 0 {
+		obj["subscription.managementgroup.name"] = managementGroupList[0]
+
+		pathList := []string{}
+		for i := len(managementGroupList) - 1; i >= 0; i-- {
+			pathList = append(pathList, managementGroupList[i])
+		}
+		obj["subscription.managementgroup.path"] = "/" + strings.Join(pathList, "/")
+	}
+
+	// defender plans
+	standardPlanList := []string{}
+	for planName, pricing := range auditor.getDefenderPricingList(ctx, logger, subscription) {
+		pricingTier := stringPtrToStringLower((*string)(pricing.Properties.PricingTier))
+		obj[fmt.Sprintf("defender.plan.%v", planName)] = pricingTier
+		if pricingTier == "standard" {
+			standardPlanList = append(standardPlanList, planName)
+		}
+	}
+	sort.Strings(standardPlanList)
+	obj["defender.plans.standard"] = standardPlanList
+
+	list = append(list, validator.NewAzureObject(obj))
+
+	// name and tags are added by enrichment
+	auditor.enrichAzureObjects(ctx, subscription, &list)
+
+	return
+}
+
+// fetchSubscriptionManagementGroupAncestors returns the management group names (direct parent first, root last) of the subscription
+func (auditor *AzureAuditor) fetchSubscriptionManagementGroupAncestors(ctx context.Context, logger *zap.SugaredLogger, subscription *armsubscriptions.Subscription) (list []string) {
+	list = []string{}
+
+	client, err := armresourcegraph.NewClient(auditor.azure.client.GetCred(), nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	queryFormat := armresourcegraph.ResultFormatObjectArray
+	queryRequest := armresourcegraph.QueryRequest{
+		Query: to.StringPtr(SubscriptionManagementGroupResourceGraphQuery),
+		Options: &armresourcegraph.QueryRequestOptions{
+			ResultFormat: &queryFormat,
+		},
+		Subscriptions: []*string{subscription.SubscriptionID},
+	}
+
+	result, err := client.Resources(ctx, queryRequest, nil)
+	if err != nil {
+		logger.Panic(err)
+	}
+
+	if resultList, ok := result.Data.([]interface{}); ok {
+		for _, v := range resultList {
+			row, ok := v.(map[string]interface{})
+			if !ok || !strings.EqualFold(interfaceToString(row["subscriptionId"]), to.String(subscription.SubscriptionID)) {
+				continue
+			}
+
+			if chain, ok := row["managementGroupAncestorsChain"].([]interface{}); ok {
+				for _, chainItem := range chain {
+					if managementGroup, ok := chainItem.(map[string]interface{}); ok {
+						list = append(list, strings.ToLower(interfaceToString(managementGroup["name"])))
+					}
+				}
+			}
+		}
+	}
+
+	return
+}
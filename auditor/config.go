@@ -16,6 +16,25 @@ type (
 		ResourceProviders        *validator.AuditConfigValidation `json:"resourceProviders"`
 		ResourceProviderFeatures *validator.AuditConfigValidation `json:"resourceProviderFeatures"`
 		KeyvaultAccessPolicies   *validator.AuditConfigValidation `json:"keyvaultAccessPolicies"`
+		NetworkSecurityGroups    *validator.AuditConfigValidation `json:"networkSecurityGroups"`
+		StorageAccounts          *validator.AuditConfigValidation `json:"storageAccounts"`
+		DenyAssignments          *validator.AuditConfigValidation `json:"denyAssignments"`
+		ClassicAdministrators    *validator.AuditConfigValidation `json:"classicAdministrators"`
+		Applications             *validator.AuditConfigValidation `json:"applications"`
+		PolicyCompliance         *validator.AuditConfigValidation `json:"policyCompliance"`
+		DiagnosticSettings       *validator.AuditConfigValidation `json:"diagnosticSettings"`
+		ResourceLocks            *validator.AuditConfigValidation `json:"resourceLocks"`
+		KeyvaultSettings         *validator.AuditConfigValidation `json:"keyvaultSettings"`
+		KeyvaultItems            *validator.AuditConfigValidation `json:"keyvaultItems"`
+		RoleDefinitions          *validator.AuditConfigValidation `json:"roleDefinitions"`
+		Resources                *validator.AuditConfigValidation `json:"resources"`
+		Subscriptions            *validator.AuditConfigValidation `json:"subscriptions"`
+		DefenderPlans            *validator.AuditConfigValidation `json:"defenderPlans"`
+		ConditionalAccess        *validator.AuditConfigValidation `json:"conditionalAccess"`
+		DirectoryRoles           *validator.AuditConfigValidation `json:"directoryRoles"`
+		FederatedCredentials     *validator.AuditConfigValidation `json:"federatedCredentials"`
+		PublicIPs                *validator.AuditConfigValidation `json:"publicIPs"`
+		Databases                *validator.AuditConfigValidation `json:"databases"`
 		ResourceGraph            *AuditConfigResourceGraph        `json:"resourceGraph"`
 		LogAnalytics             *AuditConfiLogAnalytics          `json:"logAnalytics"`
 	}
@@ -1,8 +1,11 @@
 package auditor
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/webdevops/go-common/utils/to"
 )
 
@@ -19,6 +22,20 @@ func stringPtrToStringLower(val *string) string {
 	return strings.ToLower(to.String(val))
 }
 
+func boolPtrToString(val *bool) string {
+	if val == nil {
+		return ""
+	}
+	return strconv.FormatBool(*val)
+}
+
+func int32PtrToInt64(val *int32) int64 {
+	if val == nil {
+		return 0
+	}
+	return int64(*val)
+}
+
 func azureTagsToAzureObjectField(tags map[string]*string) map[string]interface{} {
 	ret := map[string]interface{}{}
 	for tagName, tagValue := range to.StringMap(tags) {
@@ -26,3 +43,38 @@ func azureTagsToAzureObjectField(tags map[string]*string) map[string]interface{}
 	}
 	return ret
 }
+
+func interfaceToString(val interface{}) string {
+	if v, ok := val.(string); ok {
+		return v
+	}
+	return ""
+}
+
+// azureResponseStatusCode returns the http status code of an azure sdk response error (0 for other errors, eg. transport errors)
+func azureResponseStatusCode(err error) int {
+	var responseErr *azcore.ResponseError
+	if errors.As(err, &responseErr) {
+		return responseErr.StatusCode
+	}
+	return 0
+}
+
+// normalizeTlsVersion returns the lowest tls version (eg. "1.2") of values like "1.2", "TLSv1.2" or "TLSv1.2,TLSv1.3"
+func normalizeTlsVersion(val string) (version string) {
+	for _, part := range strings.Split(val, ",") {
+		part = strings.TrimSpace(strings.ToLower(part))
+		part = strings.TrimPrefix(part, "tlsv")
+		part = strings.TrimPrefix(part, "tls")
+		part = strings.ReplaceAll(part, "_", ".")
+		if part == "" {
+			continue
+		}
+
+		if version == "" || part < version {
+			version = part
+		}
+	}
+
+	return
+}
@@ -0,0 +1,29 @@
+package auditor
+
+import (
+	"testing"
+)
+
+func TestNormalizeTlsVersion(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		// storage accounts
+		{"TLS1_0", "1.0"},
+		{"TLS1_2", "1.2"},
+		// sql servers
+		{"1.2", "1.2"},
+		// postgresql and mysql flexible servers
+		{"TLSv1.2", "1.2"},
+		{"TLSv1.2,TLSv1.3", "1.2"},
+		{"TLSv1.3, TLSv1.1", "1.1"},
+		{"", ""},
+	}
+
+	for _, testCase := range testCases {
+		if result := normalizeTlsVersion(testCase.value); result != testCase.expected {
+			t.Errorf("value \"%v\": expected \"%v\", got \"%v\"", testCase.value, testCase.expected, result)
+		}
+	}
+}
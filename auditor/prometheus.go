@@ -7,10 +7,32 @@ import (
 type (
 	auditorPrometheus struct {
 		roleAssignment          *prometheus.GaugeVec
+		roleAssignmentMgmtGroup *prometheus.GaugeVec
 		resourceGroup           *prometheus.GaugeVec
 		resourceProvider        *prometheus.GaugeVec
 		resourceProviderFeature *prometheus.GaugeVec
 		keyvaultAccessPolicies  *prometheus.GaugeVec
+		networkSecurityGroup    *prometheus.GaugeVec
+		storageAccount          *prometheus.GaugeVec
+		denyAssignment          *prometheus.GaugeVec
+		classicAdministrator    *prometheus.GaugeVec
+		application             *prometheus.GaugeVec
+		policyCompliance        *prometheus.GaugeVec
+		diagnosticSetting       *prometheus.GaugeVec
+		resourceLock            *prometheus.GaugeVec
+		keyvaultSettings        *prometheus.GaugeVec
+		keyvaultItem            *prometheus.GaugeVec
+		roleDefinition          *prometheus.GaugeVec
+		resource                *prometheus.GaugeVec
+		subscription            *prometheus.GaugeVec
+		defenderPlan            *prometheus.GaugeVec
+		defenderSecureScore     *prometheus.GaugeVec
+		conditionalAccess       *prometheus.GaugeVec
+		directoryRole           *prometheus.GaugeVec
+		federatedCredential     *prometheus.GaugeVec
+		federatedCredentialApp  *prometheus.GaugeVec
+		publicIP                *prometheus.GaugeVec
+		database                *prometheus.GaugeVec
 		resourceGraph           map[string]*prometheus.GaugeVec
 		logAnalytics            map[string]*prometheus.GaugeVec
 	}
@@ -21,6 +43,10 @@ func (auditor *AzureAuditor) initPrometheus() {
 		prometheus.Unregister(auditor.prometheus.roleAssignment)
 	}
 
+	if auditor.prometheus.roleAssignmentMgmtGroup != nil {
+		prometheus.Unregister(auditor.prometheus.roleAssignmentMgmtGroup)
+	}
+
 	if auditor.prometheus.resourceGroup != nil {
 		prometheus.Unregister(auditor.prometheus.resourceGroup)
 	}
@@ -37,6 +63,90 @@ func (auditor *AzureAuditor) initPrometheus() {
 		prometheus.Unregister(auditor.prometheus.keyvaultAccessPolicies)
 	}
 
+	if auditor.prometheus.networkSecurityGroup != nil {
+		prometheus.Unregister(auditor.prometheus.networkSecurityGroup)
+	}
+
+	if auditor.prometheus.storageAccount != nil {
+		prometheus.Unregister(auditor.prometheus.storageAccount)
+	}
+
+	if auditor.prometheus.denyAssignment != nil {
+		prometheus.Unregister(auditor.prometheus.denyAssignment)
+	}
+
+	if auditor.prometheus.classicAdministrator != nil {
+		prometheus.Unregister(auditor.prometheus.classicAdministrator)
+	}
+
+	if auditor.prometheus.application != nil {
+		prometheus.Unregister(auditor.prometheus.application)
+	}
+
+	if auditor.prometheus.policyCompliance != nil {
+		prometheus.Unregister(auditor.prometheus.policyCompliance)
+	}
+
+	if auditor.prometheus.diagnosticSetting != nil {
+		prometheus.Unregister(auditor.prometheus.diagnosticSetting)
+	}
+
+	if auditor.prometheus.resourceLock != nil {
+		prometheus.Unregister(auditor.prometheus.resourceLock)
+	}
+
+	if auditor.prometheus.keyvaultSettings != nil {
+		prometheus.Unregister(auditor.prometheus.keyvaultSettings)
+	}
+
+	if auditor.prometheus.keyvaultItem != nil {
+		prometheus.Unregister(auditor.prometheus.keyvaultItem)
+	}
+
+	if auditor.prometheus.roleDefinition != nil {
+		prometheus.Unregister(auditor.prometheus.roleDefinition)
+	}
+
+	if auditor.prometheus.resource != nil {
+		prometheus.Unregister(auditor.prometheus.resource)
+	}
+
+	if auditor.prometheus.subscription != nil {
+		prometheus.Unregister(auditor.prometheus.subscription)
+	}
+
+	if auditor.prometheus.defenderPlan != nil {
+		prometheus.Unregister(auditor.prometheus.defenderPlan)
+	}
+
+	if auditor.prometheus.defenderSecureScore != nil {
+		prometheus.Unregister(auditor.prometheus.defenderSecureScore)
+	}
+
+	if auditor.prometheus.conditionalAccess != nil {
+		prometheus.Unregister(auditor.prometheus.conditionalAccess)
+	}
+
+	if auditor.prometheus.directoryRole != nil {
+		prometheus.Unregister(auditor.prometheus.directoryRole)
+	}
+
+	if auditor.prometheus.federatedCredential != nil {
+		prometheus.Unregister(auditor.prometheus.federatedCredential)
+	}
+
+	if auditor.prometheus.federatedCredentialApp != nil {
+		prometheus.Unregister(auditor.prometheus.federatedCredentialApp)
+	}
+
+	if auditor.prometheus.publicIP != nil {
+		prometheus.Unregister(auditor.prometheus.publicIP)
+	}
+
+	if auditor.prometheus.database != nil {
+		prometheus.Unregister(auditor.prometheus.database)
+	}
+
 	if auditor.prometheus.resourceGraph != nil {
 		for _, metric := range auditor.prometheus.resourceGraph {
 			prometheus.Unregister(metric)
@@ -58,11 +168,27 @@ func (auditor *AzureAuditor) initPrometheus() {
 			append(
 				auditor.config.RoleAssignments.PrometheusLabels(),
 				"rule",
+				"severity",
 			),
 		)
 		prometheus.MustRegister(auditor.prometheus.roleAssignment)
 	}
 
+	if auditor.isManagementGroupRoleAssignmentAuditEnabled() {
+		auditor.prometheus.roleAssignmentMgmtGroup = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_roleassignment_managementgroup",
+				Help: "Azure ResourceManager audit RoleAssignment violation on ManagementGroup scope",
+			},
+			append(
+				auditor.config.RoleAssignments.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.roleAssignmentMgmtGroup)
+	}
+
 	if auditor.config.ResourceGroups.IsEnabled() {
 		auditor.prometheus.resourceGroup = prometheus.NewGaugeVec(
 			prometheus.GaugeOpts{
@@ -72,6 +198,7 @@ func (auditor *AzureAuditor) initPrometheus() {
 			append(
 				auditor.config.ResourceGroups.PrometheusLabels(),
 				"rule",
+				"severity",
 			),
 		)
 		prometheus.MustRegister(auditor.prometheus.resourceGroup)
@@ -86,6 +213,7 @@ func (auditor *AzureAuditor) initPrometheus() {
 			append(
 				auditor.config.ResourceProviders.PrometheusLabels(),
 				"rule",
+				"severity",
 			),
 		)
 		prometheus.MustRegister(auditor.prometheus.resourceProvider)
@@ -100,6 +228,7 @@ func (auditor *AzureAuditor) initPrometheus() {
 			append(
 				auditor.config.ResourceProviderFeatures.PrometheusLabels(),
 				"rule",
+				"severity",
 			),
 		)
 		prometheus.MustRegister(auditor.prometheus.resourceProviderFeature)
@@ -114,11 +243,322 @@ func (auditor *AzureAuditor) initPrometheus() {
 			append(
 				auditor.config.KeyvaultAccessPolicies.PrometheusLabels(),
 				"rule",
+				"severity",
 			),
 		)
 		prometheus.MustRegister(auditor.prometheus.keyvaultAccessPolicies)
 	}
 
+	if auditor.config.NetworkSecurityGroups.IsEnabled() {
+		auditor.prometheus.networkSecurityGroup = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_networksecuritygroup",
+				Help: "Azure ResourceManager audit NetworkSecurityGroup rule violation",
+			},
+			append(
+				auditor.config.NetworkSecurityGroups.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.networkSecurityGroup)
+	}
+
+	if auditor.config.StorageAccounts.IsEnabled() {
+		auditor.prometheus.storageAccount = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_storageaccount",
+				Help: "Azure ResourceManager audit StorageAccount violation",
+			},
+			append(
+				auditor.config.StorageAccounts.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.storageAccount)
+	}
+
+	if auditor.config.DenyAssignments.IsEnabled() {
+		auditor.prometheus.denyAssignment = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_denyassignment",
+				Help: "Azure ResourceManager audit DenyAssignment violation",
+			},
+			append(
+				auditor.config.DenyAssignments.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.denyAssignment)
+	}
+
+	if auditor.config.ClassicAdministrators.IsEnabled() {
+		auditor.prometheus.classicAdministrator = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_classicadministrator",
+				Help: "Azure ResourceManager audit ClassicAdministrator violation",
+			},
+			append(
+				auditor.config.ClassicAdministrators.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.classicAdministrator)
+	}
+
+	if auditor.config.Applications.IsEnabled() {
+		auditor.prometheus.application = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_application",
+				Help: "Entra ID audit Application credential violation",
+			},
+			append(
+				auditor.config.Applications.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.application)
+	}
+
+	if auditor.config.PolicyCompliance.IsEnabled() {
+		auditor.prometheus.policyCompliance = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_policycompliance",
+				Help: "Azure ResourceManager audit PolicyCompliance violation",
+			},
+			append(
+				auditor.config.PolicyCompliance.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.policyCompliance)
+	}
+
+	if auditor.config.DiagnosticSettings.IsEnabled() {
+		auditor.prometheus.diagnosticSetting = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_diagnosticsetting",
+				Help: "Azure ResourceManager audit DiagnosticSetting violation",
+			},
+			append(
+				auditor.config.DiagnosticSettings.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.diagnosticSetting)
+	}
+
+	if auditor.config.ResourceLocks.IsEnabled() {
+		auditor.prometheus.resourceLock = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_resourcelock",
+				Help: "Azure ResourceManager audit ResourceLock violation",
+			},
+			append(
+				auditor.config.ResourceLocks.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.resourceLock)
+	}
+
+	if auditor.config.KeyvaultSettings.IsEnabled() {
+		auditor.prometheus.keyvaultSettings = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_keyvaultsettings",
+				Help: "Azure ResourceManager audit Keyvault settings violation",
+			},
+			append(
+				auditor.config.KeyvaultSettings.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.keyvaultSettings)
+	}
+
+	if auditor.config.KeyvaultItems.IsEnabled() {
+		auditor.prometheus.keyvaultItem = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_keyvaultitem",
+				Help: "Azure ResourceManager audit KeyVault item (secrets, keys and certificates) violation",
+			},
+			append(
+				auditor.config.KeyvaultItems.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.keyvaultItem)
+	}
+
+	if auditor.config.RoleDefinitions.IsEnabled() {
+		auditor.prometheus.roleDefinition = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_roledefinition",
+				Help: "Azure ResourceManager audit RoleDefinition violation",
+			},
+			append(
+				auditor.config.RoleDefinitions.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.roleDefinition)
+	}
+
+	if auditor.config.Resources.IsEnabled() {
+		auditor.prometheus.resource = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_resource",
+				Help: "Azure ResourceManager audit Resource violation",
+			},
+			append(
+				auditor.config.Resources.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.resource)
+	}
+
+	if auditor.config.Subscriptions.IsEnabled() {
+		auditor.prometheus.subscription = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_subscription",
+				Help: "Azure ResourceManager audit Subscription violation",
+			},
+			append(
+				auditor.config.Subscriptions.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.subscription)
+	}
+
+	if auditor.config.DefenderPlans.IsEnabled() {
+		auditor.prometheus.defenderPlan = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_defenderplan",
+				Help: "Azure ResourceManager audit Defender plan violation",
+			},
+			append(
+				auditor.config.DefenderPlans.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.defenderPlan)
+
+		auditor.prometheus.defenderSecureScore = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_defender_securescore",
+				Help: "Azure ResourceManager Defender for Cloud secure score (percentage)",
+			},
+			[]string{
+				"subscriptionID",
+				"subscriptionName",
+			},
+		)
+		prometheus.MustRegister(auditor.prometheus.defenderSecureScore)
+	}
+
+	if auditor.config.ConditionalAccess.IsEnabled() {
+		auditor.prometheus.conditionalAccess = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_conditionalaccess",
+				Help: "Azure ResourceManager audit ConditionalAccess policy violation",
+			},
+			append(
+				auditor.config.ConditionalAccess.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.conditionalAccess)
+	}
+
+	if auditor.config.DirectoryRoles.IsEnabled() {
+		auditor.prometheus.directoryRole = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_directoryrole",
+				Help: "Azure ResourceManager audit DirectoryRole member violation",
+			},
+			append(
+				auditor.config.DirectoryRoles.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.directoryRole)
+	}
+
+	if auditor.config.FederatedCredentials.IsEnabled() {
+		auditor.prometheus.federatedCredential = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_federatedcredential",
+				Help: "Azure ResourceManager audit FederatedCredential violation of user-assigned managed identities",
+			},
+			append(
+				auditor.config.FederatedCredentials.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.federatedCredential)
+
+		auditor.prometheus.federatedCredentialApp = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_federatedcredential_application",
+				Help: "Azure ResourceManager audit FederatedCredential violation of app registrations",
+			},
+			append(
+				auditor.config.FederatedCredentials.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.federatedCredentialApp)
+	}
+
+	if auditor.config.PublicIPs.IsEnabled() {
+		auditor.prometheus.publicIP = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_publicip",
+				Help: "Azure ResourceManager audit PublicIP violation",
+			},
+			append(
+				auditor.config.PublicIPs.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.publicIP)
+	}
+
+	if auditor.config.Databases.IsEnabled() {
+		auditor.prometheus.database = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "azurerm_audit_violation_database",
+				Help: "Azure Database server and firewall rule violations",
+			},
+			append(
+				auditor.config.Databases.PrometheusLabels(),
+				"rule",
+				"severity",
+			),
+		)
+		prometheus.MustRegister(auditor.prometheus.database)
+	}
+
 	auditor.prometheus.resourceGraph = map[string]*prometheus.GaugeVec{}
 	if auditor.config.ResourceGraph.IsEnabled() {
 		for queryName, query := range auditor.config.ResourceGraph.Queries {
@@ -130,6 +570,7 @@ func (auditor *AzureAuditor) initPrometheus() {
 				append(
 					query.PrometheusLabels(),
 					"rule",
+					"severity",
 				),
 			)
 			prometheus.MustRegister(auditor.prometheus.resourceGraph[queryName])
@@ -147,6 +588,7 @@ func (auditor *AzureAuditor) initPrometheus() {
 				append(
 					query.PrometheusLabels(),
 					"rule",
+					"severity",
 				),
 			)
 			prometheus.MustRegister(auditor.prometheus.logAnalytics[queryName])
@@ -10,6 +10,7 @@ var (
 	RuleStatusIgnore RuleStatus = -1
 	RuleStatusDeny   RuleStatus = 0
 	RuleStatusAllow  RuleStatus = 1
+	RuleStatusExempt RuleStatus = 2
 )
 
 func StringToRuleStatus(val string) RuleStatus {
@@ -23,6 +24,8 @@ func StringToRuleStatus(val string) RuleStatus {
 		return RuleStatusDeny
 	case "1", "true", "allow":
 		return RuleStatusAllow
+	case "2", "exempt":
+		return RuleStatusExempt
 	}
 	return RuleStatusDeny
 }
@@ -36,6 +39,8 @@ func (s RuleStatus) String() (ret string) {
 		ret = "deny"
 	case RuleStatusAllow:
 		ret = "allow"
+	case RuleStatusExempt:
+		ret = "exempt"
 	}
 	return
 }
@@ -49,3 +54,7 @@ func (s RuleStatus) IsDeny() bool {
 func (s RuleStatus) IsAllow() bool {
 	return s == RuleStatusAllow
 }
+
+func (s RuleStatus) IsExempt() bool {
+	return s == RuleStatusExempt
+}
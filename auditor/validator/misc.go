@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
@@ -37,10 +39,37 @@ func interfaceToString(value interface{}) string {
 		return val
 	case *string:
 		return to.String(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
 	}
 	return ""
 }
 
+// interfaceToFloat64 converts numeric values (int, float, json.Number and numeric strings) to float64
+func interfaceToFloat64(value interface{}) (float64, bool) {
+	switch val := value.(type) {
+	case int:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case float32:
+		return float64(val), true
+	case float64:
+		return val, true
+	case json.Number:
+		if number, err := val.Float64(); err == nil {
+			return number, true
+		}
+	case string:
+		if number, err := strconv.ParseFloat(strings.TrimSpace(val), 64); err == nil {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
 func interfaceListToStringList(value []interface{}) []string {
 	list := []string{}
 	for _, val := range value {
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -22,6 +23,10 @@ func (o *AzureObject) ToPrometheusLabel(name string) string {
 			return v
 		case []string:
 			return strings.Join(v, ",")
+		default:
+			if number, ok := interfaceToFloat64(v); ok {
+				return strconv.FormatFloat(number, 'f', -1, 64)
+			}
 		}
 	}
 
@@ -0,0 +1,139 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+type (
+	// AuditConfigValidationRuleCondition combines field conditions (implicit AND) with
+	// nested anyOf (OR), allOf (AND) and not blocks
+	AuditConfigValidationRuleCondition struct {
+		Fields map[string]AuditConfigValidationRuleField
+
+		AnyOf []*AuditConfigValidationRuleCondition `json:"anyOf,omitempty"`
+		AllOf []*AuditConfigValidationRuleCondition `json:"allOf,omitempty"`
+		Not   *AuditConfigValidationRuleCondition   `json:"not,omitempty"`
+	}
+)
+
+func (condition *AuditConfigValidationRuleCondition) parse(config map[string]interface{}) error {
+	condition.Fields = map[string]AuditConfigValidationRuleField{}
+
+	for name, val := range config {
+		switch strings.ToLower(name) {
+		case "anyof":
+			list, err := parseRuleConditionList(name, val)
+			if err != nil {
+				return err
+			}
+			condition.AnyOf = list
+		case "allof":
+			list, err := parseRuleConditionList(name, val)
+			if err != nil {
+				return err
+			}
+			condition.AllOf = list
+		case "not":
+			v, ok := val.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%v must be a map of conditions", name)
+			}
+
+			condition.Not = &AuditConfigValidationRuleCondition{}
+			if err := condition.Not.parse(v); err != nil {
+				return err
+			}
+		case "rule", "action", "func", "description", "severity", "owner", "remediation", "references":
+			return fmt.Errorf("%v is not allowed inside nested conditions", name)
+		default:
+			condition.Fields[name] = parseRuleField(val)
+		}
+	}
+
+	return nil
+}
+
+func parseRuleConditionList(name string, val interface{}) (list []*AuditConfigValidationRuleCondition, err error) {
+	v, ok := val.([]interface{})
+	if !ok || len(v) == 0 {
+		return nil, fmt.Errorf("%v must be a non-empty list of conditions", name)
+	}
+
+	for _, row := range v {
+		conditionConfig, ok := row.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%v must be a list of condition maps", name)
+		}
+
+		condition := &AuditConfigValidationRuleCondition{}
+		if err := condition.parse(conditionConfig); err != nil {
+			return nil, err
+		}
+		list = append(list, condition)
+	}
+
+	return
+}
+
+func (condition *AuditConfigValidationRuleCondition) IsMatching(object *AzureObject) bool {
+	for fieldName, field := range condition.Fields {
+		if v, exists := (*object)[fieldName]; exists {
+			status, skipField := field.IsMatching(v)
+
+			// check if field is a continue field (eg. status cannot be applied)
+			if skipField {
+				continue
+			}
+
+			// check if status should be inverted (not)
+			if field.Not {
+				if status {
+					return false
+				} else {
+					continue
+				}
+			}
+
+			// field is not matching, object is not matching
+			if !status {
+				return false
+			}
+		} else {
+			if field.Required {
+				// required, but empty -> field is not matching, object is not matching
+				return false
+			}
+		}
+	}
+
+	// all nested conditions must match
+	for _, subCondition := range condition.AllOf {
+		if !subCondition.IsMatching(object) {
+			return false
+		}
+	}
+
+	// at least one nested condition must match
+	if len(condition.AnyOf) > 0 {
+		anyMatching := false
+		for _, subCondition := range condition.AnyOf {
+			if subCondition.IsMatching(object) {
+				anyMatching = true
+				break
+			}
+		}
+
+		if !anyMatching {
+			return false
+		}
+	}
+
+	// nested condition must not match
+	if condition.Not != nil && condition.Not.IsMatching(object) {
+		return false
+	}
+
+	// if all fields and nested conditions are matching, object is matching
+	return true
+}
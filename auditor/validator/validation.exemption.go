@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type (
+	// AuditConfigValidationExemption exempts a resource (by resource id and/or field conditions)
+	// from a specific rule until the exemption expires
+	AuditConfigValidationExemption struct {
+		Rule       string    `json:"rule"`
+		ResourceID string    `json:"resourceID,omitempty"`
+		Reason     string    `json:"reason"`
+		ApprovedBy string    `json:"approvedBy"`
+		Expires    time.Time `json:"expires"`
+
+		// FIELDS and nested conditions (anyOf, allOf, not)
+		AuditConfigValidationRuleCondition
+	}
+)
+
+func (exemption *AuditConfigValidationExemption) UnmarshalJSON(b []byte) error {
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return errors.New("invalid exemption map")
+	}
+
+	condition := map[string]interface{}{}
+	for name, val := range config {
+		switch strings.ToLower(name) {
+		case "rule":
+			exemption.Rule = interfaceToString(val)
+		case "resourceid":
+			exemption.ResourceID = interfaceToString(val)
+		case "reason":
+			exemption.Reason = interfaceToString(val)
+		case "approvedby":
+			exemption.ApprovedBy = interfaceToString(val)
+		case "expires":
+			expires := parseTime(interfaceToString(val))
+			if expires == nil {
+				return fmt.Errorf("unable to parse expires value \"%v\" of exemption for rule \"%v\"", val, config["rule"])
+			}
+			exemption.Expires = *expires
+		default:
+			condition[name] = val
+		}
+	}
+
+	if err := exemption.AuditConfigValidationRuleCondition.parse(condition); err != nil {
+		return fmt.Errorf("unable to parse exemption for rule \"%v\": %w", exemption.Rule, err)
+	}
+
+	switch {
+	case exemption.Rule == "":
+		return errors.New("exemption requires a rule")
+	case exemption.Reason == "":
+		return fmt.Errorf("exemption for rule \"%v\" requires a reason", exemption.Rule)
+	case exemption.ApprovedBy == "":
+		return fmt.Errorf("exemption for rule \"%v\" requires approvedBy", exemption.Rule)
+	case exemption.Expires.IsZero():
+		return fmt.Errorf("exemption for rule \"%v\" requires an expires date", exemption.Rule)
+	case exemption.ResourceID == "" && len(exemption.Fields) == 0 && len(exemption.AnyOf) == 0 && len(exemption.AllOf) == 0 && exemption.Not == nil:
+		// an exemption without any matcher would exempt every object
+		return fmt.Errorf("exemption for rule \"%v\" requires a resourceID or field conditions", exemption.Rule)
+	}
+
+	return nil
+}
+
+func (exemption *AuditConfigValidationExemption) IsExpired() bool {
+	return time.Now().After(exemption.Expires)
+}
+
+func (exemption *AuditConfigValidationExemption) IsMatching(ruleId string, object *AzureObject) bool {
+	if !strings.EqualFold(exemption.Rule, ruleId) {
+		return false
+	}
+
+	if exemption.ResourceID != "" && !strings.EqualFold(exemption.ResourceID, object.ResourceID()) {
+		return false
+	}
+
+	return exemption.AuditConfigValidationRuleCondition.IsMatching(object)
+}
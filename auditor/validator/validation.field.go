@@ -1,9 +1,14 @@
 package validator
 
 import (
+	"encoding/json"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/webdevops/go-common/utils/to"
 )
 
 type (
@@ -25,8 +30,10 @@ type (
 		AnyOf *[]string `json:"anyOf,omitempty"`
 
 		// NUMERIC
-		Min *float64 `json:"min,omitempty"`
-		Max *float64 `json:"max,omitempty"`
+		Min      *float64 `json:"min,omitempty"`
+		Max      *float64 `json:"max,omitempty"`
+		Equal    *float64 `json:"equal,omitempty"`
+		NotEqual *float64 `json:"notEqual,omitempty"`
 
 		// DURATION
 		MinDuration *time.Duration `json:"minDuration,omitempty"`
@@ -34,6 +41,122 @@ type (
 	}
 )
 
+func parseRuleField(val interface{}) AuditConfigValidationRuleField {
+	switch v := val.(type) {
+	case string:
+		return AuditConfigValidationRuleField{
+			Required: true,
+			Match:    &v,
+		}
+	case float64:
+		return AuditConfigValidationRuleField{
+			Required: true,
+			Equal:    &v,
+		}
+	case []string:
+		return AuditConfigValidationRuleField{
+			Required: true,
+			AllOf:    &v,
+		}
+	case []interface{}:
+		list := []string{}
+		for _, val := range v {
+			if v, ok := val.(string); ok {
+				list = append(list, v)
+			}
+		}
+		return AuditConfigValidationRuleField{
+			Required: true,
+			AllOf:    &list,
+		}
+	case map[string]interface{}:
+		// normalize map
+		tmp := map[string]interface{}{}
+		for tmpName, tmpValue := range v {
+			tmpName = strings.ToLower(tmpName)
+			tmp[tmpName] = tmpValue
+		}
+		v = tmp
+
+		ruleField := AuditConfigValidationRuleField{
+			Required: true,
+		}
+
+		if x, ok := v["not"].(bool); ok {
+			ruleField.Not = x
+		}
+
+		if x, ok := v["required"].(bool); ok {
+			ruleField.Required = x
+		}
+		if x, ok := v["parseas"].(string); ok {
+			switch x {
+			case "duration":
+				ruleField.ParseAs = to.StringPtr("duration")
+			case "timesince":
+				ruleField.ParseAs = to.StringPtr("timesince")
+			case "number":
+				ruleField.ParseAs = to.StringPtr("number")
+			default:
+				panic(fmt.Sprintf("parseAs value \"%v\" is not allowed", x))
+			}
+		}
+
+		if x, ok := v["match"].(string); ok {
+			ruleField.Match = &x
+		}
+
+		if x, ok := v["allof"].([]interface{}); ok {
+			x := interfaceListToStringList(x)
+			ruleField.AllOf = &x
+		}
+
+		if x, ok := v["anyof"].([]interface{}); ok {
+			x := interfaceListToStringList(x)
+			ruleField.AnyOf = &x
+		}
+
+		if x, ok := v["regexp"].(string); ok {
+			ruleField.Regexp = &x
+			ruleField.regexp = regexp.MustCompile(x)
+		}
+
+		for numberName, numberField := range map[string]**float64{
+			"min":      &ruleField.Min,
+			"max":      &ruleField.Max,
+			"equal":    &ruleField.Equal,
+			"notequal": &ruleField.NotEqual,
+		} {
+			if x, exists := v[numberName]; exists {
+				if number, ok := interfaceToFloat64(x); ok {
+					*numberField = &number
+				} else {
+					panic(fmt.Sprintf("unable to parse %v value \"%v\"", numberName, x))
+				}
+			}
+		}
+
+		if x, ok := v["minduration"].(string); ok {
+			if dur, err := time.ParseDuration(x); err == nil {
+				ruleField.MinDuration = &dur
+			} else {
+				panic(fmt.Sprintf("unable to parse minDuration value \"%v\"", x))
+			}
+		}
+		if x, ok := v["maxduration"].(string); ok {
+			if dur, err := time.ParseDuration(x); err == nil {
+				ruleField.MaxDuration = &dur
+			} else {
+				panic(fmt.Sprintf("unable to parse maxDuration value \"%v\"", x))
+			}
+		}
+
+		return ruleField
+	default:
+		panic(v)
+	}
+}
+
 func (field *AuditConfigValidationRuleField) IsMatching(v interface{}) (bool, bool) {
 	switch fieldValue := v.(type) {
 	// STRING type
@@ -64,6 +187,20 @@ func (field *AuditConfigValidationRuleField) IsMatching(v interface{}) (bool, bo
 					// parse failed, not matching
 					return false, false
 				}
+			case "number":
+				if number, err := strconv.ParseFloat(strings.TrimSpace(fieldValue), 64); err == nil {
+					return field.IsMatching(number)
+				} else {
+					// parse failed, not matching
+					return false, false
+				}
+			}
+		}
+
+		if field.hasNumberCondition() {
+			// numeric conditions without parseAs, only numeric strings can match
+			if number, err := strconv.ParseFloat(strings.TrimSpace(fieldValue), 64); err != nil || !field.isMatchingNumberCondition(number) {
+				return false, false
 			}
 		}
 
@@ -136,6 +273,16 @@ func (field *AuditConfigValidationRuleField) IsMatching(v interface{}) (bool, bo
 			return false, false
 		}
 
+	// NUMERIC type
+	case int, int32, int64, float32, float64, json.Number:
+		number, ok := interfaceToFloat64(fieldValue)
+		if !ok {
+			// not a valid number (eg. invalid json.Number)
+			return false, false
+		}
+
+		return field.isMatchingNumber(number), false
+
 	// UNKNOWN type
 	default:
 		return false, false
@@ -143,3 +290,53 @@ func (field *AuditConfigValidationRuleField) IsMatching(v interface{}) (bool, bo
 
 	return true, false
 }
+
+// hasNumberCondition returns true if the field has numeric conditions (min, max, equal or notEqual)
+func (field *AuditConfigValidationRuleField) hasNumberCondition() bool {
+	return field.Min != nil || field.Max != nil || field.Equal != nil || field.NotEqual != nil
+}
+
+// isMatchingNumberCondition validates the numeric conditions (min, max, equal and notEqual)
+func (field *AuditConfigValidationRuleField) isMatchingNumberCondition(number float64) bool {
+	if field.Min != nil && number < *field.Min {
+		return false
+	}
+
+	if field.Max != nil && number > *field.Max {
+		return false
+	}
+
+	if field.Equal != nil && number != *field.Equal {
+		return false
+	}
+
+	if field.NotEqual != nil && number == *field.NotEqual {
+		return false
+	}
+
+	return true
+}
+
+func (field *AuditConfigValidationRuleField) isMatchingNumber(number float64) bool {
+	if !field.isMatchingNumberCondition(number) {
+		return false
+	}
+
+	if field.Match != nil {
+		// direct matching (eg. "field: '5'"), must be a number
+		if match, ok := interfaceToFloat64(*field.Match); !ok || number != match {
+			return false
+		}
+	}
+
+	if field.AnyOf != nil {
+		for _, val := range *field.AnyOf {
+			if match, ok := interfaceToFloat64(val); ok && number == match {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/robertkrimen/otto"
+	_ "github.com/robertkrimen/otto/underscore"
+	"go.uber.org/zap"
+)
+
+const (
+	// FuncStackDepthLimit is the max call stack depth of a javascript func rule (eg. runaway recursion)
+	FuncStackDepthLimit = 1000
+)
+
+var (
+	// funcVm is the base javascript runtime, it is only used for compiling and
+	// as template for the per evaluation runtimes (never runs any rule itself)
+	funcVm = newFuncBaseVm()
+
+	// funcVmTemplates are copies of the base runtime which never run any rule itself, copying locks the
+	// source runtime so multiple templates are used to create the per evaluation runtimes in parallel
+	funcVmTemplates = newFuncVmTemplates(runtime.GOMAXPROCS(0))
+
+	// FuncTimeout is the max execution time of a javascript func rule per object (0 = no timeout)
+	//
+	// otto doesn't provide any way to limit the memory (heap) usage of a runtime, only the execution time
+	// and the call stack depth are limited; func rules must only be used with trusted configuration files
+	FuncTimeout = 1 * time.Second
+
+	errFuncTimeout = errors.New("func execution timeout")
+)
+
+func newFuncBaseVm() *otto.Otto {
+	vm := otto.New()
+	vm.SetStackDepthLimit(FuncStackDepthLimit)
+	return vm
+}
+
+func newFuncVmTemplates(count int) chan *otto.Otto {
+	templates := make(chan *otto.Otto, count)
+	for i := 0; i < count; i++ {
+		templates <- funcVm.Copy()
+	}
+	return templates
+}
+
+// newFuncVm returns a new runtime which never ran any user code, runtimes must not be reused
+// as scripts can modify globals and builtins (eg. Array.prototype) which would leak into other evaluations
+func newFuncVm() *otto.Otto {
+	template := <-funcVmTemplates
+	defer func() {
+		funcVmTemplates <- template
+	}()
+
+	return template.Copy()
+}
+
+// runFunc runs the javascript func rule for the object, an error is returned if the func failed or
+// was stopped after FuncTimeout (the object must not be treated as matching or not matching)
+func (matcher *AuditConfigValidationRule) runFunc(object *AzureObject) (status bool, err error) {
+	// each evaluation uses its own runtime so rules can run in parallel
+	// (eg. per subscription goroutines) and cannot leak state into other rules or objects
+	vm := newFuncVm()
+
+	if FuncTimeout > 0 {
+		vm.Interrupt = make(chan func(), 1)
+		timer := time.AfterFunc(FuncTimeout, func() {
+			vm.Interrupt <- func() {
+				panic(errFuncTimeout)
+			}
+		})
+		defer timer.Stop()
+	}
+
+	defer func() {
+		if caught := recover(); caught != nil {
+			if caught != errFuncTimeout {
+				panic(caught)
+			}
+
+			// runaway script
+			status = false
+			err = fmt.Errorf("func was stopped after %v", FuncTimeout.String())
+		}
+
+		if err != nil && Logger != nil {
+			Logger.With(
+				zap.String("resourceID", object.ResourceID()),
+				zap.String("rule", matcher.Rule),
+			).Error(err)
+		}
+	}()
+
+	if err := vm.Set("obj", *object); err != nil {
+		panic(err)
+	}
+
+	result, err := vm.Run(matcher.customFunction)
+	if err != nil {
+		return false, fmt.Errorf("func failed: %w", err)
+	}
+
+	status, err = result.ToBoolean()
+	if err != nil {
+		return false, fmt.Errorf("func result is not a boolean: %w", err)
+	}
+	return status, nil
+}
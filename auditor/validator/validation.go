@@ -2,8 +2,10 @@ package validator
 
 import (
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 
 	"github.com/webdevops/azure-auditor/auditor/types"
 )
@@ -22,6 +24,18 @@ type (
 		Mapping              *map[string]string                      `json:"mapping,omitempty"`
 		Enrich               bool                                    `json:"enrich,omitempty"`
 		ScopeRules           map[string][]*AuditConfigValidationRule `json:"scopeRules,omitempty"`
+		Exemptions           []*AuditConfigValidationExemption       `json:"exemptions,omitempty"`
+	}
+
+	// AuditConfigValidationResult is the result of the validation of an object with the matching rule
+	// and its metadata (empty for the default deny), the exemption if the object is exempted
+	// and the error if the rule couldn't be evaluated (object is denied)
+	AuditConfigValidationResult struct {
+		RuleID    string
+		Status    types.RuleStatus
+		Metadata  AuditConfigValidationRuleMetadata
+		Exemption *AuditConfigValidationExemption
+		Error     string
 	}
 
 	AuditConfigValidationPrometheus struct {
@@ -87,9 +101,10 @@ func (validation *AuditConfigValidation) PrometheusLabels() []string {
 	return labels
 }
 
-func (validation *AuditConfigValidation) CreatePrometheusMetricFromAzureObject(obj *AzureObject, ruleId string) prometheus.Labels {
+func (validation *AuditConfigValidation) CreatePrometheusMetricFromAzureObject(obj *AzureObject, result *AuditConfigValidationResult) prometheus.Labels {
 	labels := prometheus.Labels{
-		"rule": ruleId,
+		"rule":     result.RuleID,
+		"severity": result.Metadata.Severity,
 	}
 
 	for labelName, fieldName := range validation.Prometheus.Labels {
@@ -99,46 +114,82 @@ func (validation *AuditConfigValidation) CreatePrometheusMetricFromAzureObject(o
 	return labels
 }
 
-func (validation *AuditConfigValidation) Validate(object *AzureObject) (string, types.RuleStatus) {
-	resourceID := object.ResourceID()
+func (validation *AuditConfigValidation) Validate(object *AzureObject) *AuditConfigValidationResult {
+	result := validation.validateRules(object)
 
-	if validation.Rules != nil {
-		for _, rule := range validation.Rules {
-			if rule.IsActionContinue() {
-				if rule.IsMatching(object) {
-					// valid object, proceed with next rule
-					continue
-				} else {
-					// valid is not valid, returning here
-					return rule.Rule, rule.handleRuleStatus(object, types.RuleStatusDeny)
-				}
+	// exemptions (only for denied objects, failed evaluations are never exempted)
+	if result.Status.IsDeny() && result.Error == "" {
+		for _, exemption := range validation.Exemptions {
+			if !exemption.IsMatching(result.RuleID, object) {
+				continue
 			}
 
-			if rule.IsMatching(object) {
-				return rule.Rule, rule.handleRuleStatus(object, *rule.ValidationStatus())
+			if exemption.IsExpired() {
+				// expired exemption, object is denied again
+				if Logger != nil {
+					Logger.With(
+						zap.String("resourceID", object.ResourceID()),
+						zap.String("rule", result.RuleID),
+					).Warnf("exemption expired on %v (approved by \"%v\")", exemption.Expires.Format(time.RFC3339), exemption.ApprovedBy)
+				}
+				continue
 			}
+
+			result.Status = types.RuleStatusExempt
+			result.Exemption = exemption
+			return result
+		}
+	}
+
+	return result
+}
+
+func (validation *AuditConfigValidation) validateRules(object *AzureObject) *AuditConfigValidationResult {
+	resourceID := object.ResourceID()
+
+	if validation.Rules != nil {
+		if result := validateRuleList(validation.Rules, object); result != nil {
+			return result
 		}
 	}
 
 	for scopePrefix, rules := range validation.ScopeRules {
 		if strings.HasPrefix(resourceID, scopePrefix) {
-			for _, rule := range rules {
-				if rule.IsActionContinue() {
-					if rule.IsMatching(object) {
-						// valid object, proceed with next rule
-						continue
-					} else {
-						// valid is not valid, returning here
-						return rule.Rule, rule.handleRuleStatus(object, types.RuleStatusDeny)
-					}
-				}
+			if result := validateRuleList(rules, object); result != nil {
+				return result
+			}
+		}
+	}
 
-				if rule.IsMatching(object) {
-					return rule.Rule, rule.handleRuleStatus(object, *rule.ValidationStatus())
-				}
+	return &AuditConfigValidationResult{
+		RuleID: "__DEFAULTDENY__",
+		Status: types.RuleStatusDeny,
+	}
+}
+
+// validateRuleList validates the object against the rules, nil if no rule was matching
+func validateRuleList(rules []*AuditConfigValidationRule, object *AzureObject) *AuditConfigValidationResult {
+	for _, rule := range rules {
+		matching, err := rule.isMatching(object)
+		if err != nil {
+			// rule couldn't be evaluated (eg. func timeout), fail closed
+			return rule.newValidationResult(rule.handleRuleStatus(object, types.RuleStatusDeny), err)
+		}
+
+		if rule.IsActionContinue() {
+			if matching {
+				// valid object, proceed with next rule
+				continue
+			} else {
+				// valid is not valid, returning here
+				return rule.newValidationResult(rule.handleRuleStatus(object, types.RuleStatusDeny), nil)
 			}
 		}
+
+		if matching {
+			return rule.newValidationResult(rule.handleRuleStatus(object, *rule.ValidationStatus()), nil)
+		}
 	}
 
-	return "__DEFAULTDENY__", types.RuleStatusDeny
+	return nil
 }
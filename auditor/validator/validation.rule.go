@@ -4,16 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"regexp"
+	"slices"
 	"strings"
-	"sync"
 	"sync/atomic"
-	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/robertkrimen/otto"
-	_ "github.com/robertkrimen/otto/underscore"
-	"github.com/webdevops/go-common/utils/to"
 	"go.uber.org/zap"
 
 	"github.com/webdevops/azure-auditor/auditor/types"
@@ -22,9 +18,14 @@ import (
 type (
 	AuditConfigValidationRule struct {
 		Rule   string `json:"rule"`
-		Fields map[string]AuditConfigValidationRuleField
 		Action string `json:"action"`
 
+		// METADATA (description, severity, owner, remediation, references)
+		AuditConfigValidationRuleMetadata
+
+		// FIELDS and nested conditions (anyOf, allOf, not)
+		AuditConfigValidationRuleCondition
+
 		// FUNC
 		CustomFunction *string `json:"func,omitempty"`
 		customFunction *otto.Script
@@ -35,19 +36,43 @@ type (
 	AuditConfigValidationRuleStats struct {
 		Matches int64 `json:"matches"`
 	}
+
+	AuditConfigValidationRuleMetadata struct {
+		Description string   `json:"description,omitempty"`
+		Severity    string   `json:"severity,omitempty"`
+		Owner       string   `json:"owner,omitempty"`
+		Remediation string   `json:"remediation,omitempty"`
+		References  []string `json:"references,omitempty"`
+	}
+)
+
+var (
+	RuleSeverities = []string{"info", "low", "medium", "high", "critical"}
 )
 
 var (
-	vm     = otto.New()
-	vmLock = sync.Mutex{}
 	Logger *zap.SugaredLogger
 )
 
+func (matcher *AuditConfigValidationRule) newValidationResult(status types.RuleStatus, err error) *AuditConfigValidationResult {
+	result := &AuditConfigValidationResult{
+		RuleID:   matcher.Rule,
+		Status:   status,
+		Metadata: matcher.AuditConfigValidationRuleMetadata,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
 func (matcher *AuditConfigValidationRule) UnmarshalJSON(b []byte) error {
 	config := map[string]interface{}{}
 	err := json.Unmarshal(b, &config)
 	if err == nil {
-		matcher.Fields = map[string]AuditConfigValidationRuleField{}
+		condition := map[string]interface{}{}
 		matcher.Action = "allow"
 
 		for name, val := range config {
@@ -56,116 +81,42 @@ func (matcher *AuditConfigValidationRule) UnmarshalJSON(b []byte) error {
 				matcher.Rule = interfaceToString(val)
 			case "action":
 				matcher.Action = interfaceToString(val)
+			case "description":
+				matcher.Description = interfaceToString(val)
+			case "severity":
+				matcher.Severity = strings.ToLower(interfaceToString(val))
+				if !slices.Contains(RuleSeverities, matcher.Severity) {
+					return fmt.Errorf("severity value \"%v\" is not allowed (allowed: %v)", val, strings.Join(RuleSeverities, ", "))
+				}
+			case "owner":
+				matcher.Owner = interfaceToString(val)
+			case "remediation":
+				matcher.Remediation = interfaceToString(val)
+			case "references":
+				switch v := val.(type) {
+				case string:
+					matcher.References = []string{v}
+				case []interface{}:
+					matcher.References = interfaceListToStringList(v)
+				default:
+					return fmt.Errorf("references must be a string or a list of strings")
+				}
 			case "func":
 				funcString := interfaceToString(val)
 				matcher.CustomFunction = &funcString
-				if funcCall, err := vm.Compile("", funcString); err == nil {
+				if funcCall, err := funcVm.Compile("", funcString); err == nil {
 					matcher.customFunction = funcCall
 				} else {
 					return fmt.Errorf("unable to parse func: %w\n\n%v", err, funcString)
 				}
 			default:
-				switch v := val.(type) {
-				case string:
-					matcher.Fields[name] = AuditConfigValidationRuleField{
-						Required: true,
-						Match:    &v,
-					}
-				case []string:
-					matcher.Fields[name] = AuditConfigValidationRuleField{
-						Required: true,
-						AllOf:    &v,
-					}
-				case []interface{}:
-					list := []string{}
-					for _, val := range v {
-						if v, ok := val.(string); ok {
-							list = append(list, v)
-						}
-					}
-					matcher.Fields[name] = AuditConfigValidationRuleField{
-						Required: true,
-						AllOf:    &list,
-					}
-				case map[string]interface{}:
-					// normalize map
-					tmp := map[string]interface{}{}
-					for tmpName, tmpValue := range v {
-						tmpName = strings.ToLower(tmpName)
-						tmp[tmpName] = tmpValue
-					}
-					v = tmp
-
-					ruleField := AuditConfigValidationRuleField{
-						Required: true,
-					}
-
-					if x, ok := v["not"].(bool); ok {
-						ruleField.Not = x
-					}
-
-					if x, ok := v["required"].(bool); ok {
-						ruleField.Required = x
-					}
-					if x, ok := v["parseas"].(string); ok {
-						switch x {
-						case "duration":
-							ruleField.ParseAs = to.StringPtr("duration")
-						case "timesince":
-							ruleField.ParseAs = to.StringPtr("timesince")
-						default:
-							panic(fmt.Sprintf("parseAs value \"%v\" is not allowed", x))
-						}
-					}
-
-					if x, ok := v["match"].(string); ok {
-						ruleField.Match = &x
-					}
-
-					if x, ok := v["allof"].([]interface{}); ok {
-						x := interfaceListToStringList(x)
-						ruleField.AllOf = &x
-					}
-
-					if x, ok := v["anyof"].([]interface{}); ok {
-						x := interfaceListToStringList(x)
-						ruleField.AnyOf = &x
-					}
-
-					if x, ok := v["regexp"].(string); ok {
-						ruleField.Regexp = &x
-						ruleField.regexp = regexp.MustCompile(x)
-					}
-
-					if x, ok := v["min"].(float64); ok {
-						ruleField.Min = &x
-					}
-
-					if x, ok := v["max"].(float64); ok {
-						ruleField.Max = &x
-					}
-
-					if x, ok := v["minduration"].(string); ok {
-						if dur, err := time.ParseDuration(x); err == nil {
-							ruleField.MinDuration = &dur
-						} else {
-							panic(fmt.Sprintf("unable to parse minDuration value \"%v\"", x))
-						}
-					}
-					if x, ok := v["maxduration"].(string); ok {
-						if dur, err := time.ParseDuration(x); err == nil {
-							ruleField.MaxDuration = &dur
-						} else {
-							panic(fmt.Sprintf("unable to parse maxDuration value \"%v\"", x))
-						}
-					}
-
-					matcher.Fields[name] = ruleField
-				default:
-					panic(v)
-				}
+				condition[name] = val
 			}
 		}
+
+		if err := matcher.AuditConfigValidationRuleCondition.parse(condition); err != nil {
+			return fmt.Errorf("unable to parse rule \"%v\": %w", matcher.Rule, err)
+		}
 	} else {
 		return errors.New("invalid rule map")
 	}
@@ -213,60 +164,15 @@ func (matcher *AuditConfigValidationRule) ValidationStatus() (ret *types.RuleSta
 }
 
 func (matcher *AuditConfigValidationRule) IsMatching(object *AzureObject) bool {
-	if matcher.customFunction != nil {
-		return matcher.runFunc(object)
-	}
-
-	for fieldName, field := range matcher.Fields {
-		if v, exists := (*object)[fieldName]; exists {
-			status, skipField := field.IsMatching(v)
-
-			// check if field is a continue field (eg. status cannot be applied)
-			if skipField {
-				continue
-			}
-
-			// check if status should be inverted (not)
-			if field.Not {
-				if status {
-					return false
-				} else {
-					continue
-				}
-			}
-
-			// field is not matching, object is not matching
-			if !status {
-				return false
-			}
-		} else {
-			if field.Required {
-				// required, but empty -> field is not matching, object is not matching
-				return false
-			}
-		}
-	}
-
-	// if all fields are matching, object is matching
-	return true
+	status, _ := matcher.isMatching(object)
+	return status
 }
 
-func (matcher *AuditConfigValidationRule) runFunc(object *AzureObject) bool {
-	vmLock.Lock()
-	defer vmLock.Unlock()
-
-	if err := vm.Set("obj", *object); err != nil {
-		panic(err)
-	}
-
-	result, err := vm.Run(matcher.customFunction)
-	if err != nil {
-		panic(err)
+// isMatching validates the object, an error is returned if the rule couldn't be evaluated (eg. func timeout)
+func (matcher *AuditConfigValidationRule) isMatching(object *AzureObject) (bool, error) {
+	if matcher.customFunction != nil {
+		return matcher.runFunc(object)
 	}
 
-	status, err := result.ToBoolean()
-	if err != nil {
-		panic(err)
-	}
-	return status
+	return matcher.AuditConfigValidationRuleCondition.IsMatching(object), nil
 }
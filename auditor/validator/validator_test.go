@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"encoding/json"
+	"sync"
 	"testing"
 	"time"
 
@@ -48,8 +50,8 @@ test:
 			"resourcegroup.tag.foobar": "barfoo",
 		},
 	)
-	if _, status := config.Test.Validate(obj); !status.IsAllow() {
-		t.Errorf("expected matching object, got: %v", status)
+	if result := config.Test.Validate(obj); !result.Status.IsAllow() {
+		t.Errorf("expected matching object, got: %v", result.Status)
 	}
 
 	obj = NewAzureObject(
@@ -58,8 +60,8 @@ test:
 			"resourcegroup.tag.barfoo": "foobar",
 		},
 	)
-	if _, status := config.Test.Validate(obj); !status.IsDeny() {
-		t.Errorf("expected NOT matching object, got: %v", status)
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() {
+		t.Errorf("expected NOT matching object, got: %v", result.Status)
 	}
 
 	obj = NewAzureObject(
@@ -68,8 +70,8 @@ test:
 			"resourcegroup.tag.foobar": "barfoo",
 		},
 	)
-	if _, status := config.Test.Validate(obj); !status.IsIgnore() {
-		t.Errorf("expected NOT matching object, got: %v", status)
+	if result := config.Test.Validate(obj); !result.Status.IsIgnore() {
+		t.Errorf("expected NOT matching object, got: %v", result.Status)
 	}
 }
 
@@ -136,8 +138,8 @@ test:
 			"principal.type":           "group",
 		},
 	)
-	if ruleId, status := config.Test.Validate(obj); !status.IsAllow() {
-		t.Errorf("expected matching object, got: %v by rule %v", status, ruleId)
+	if result := config.Test.Validate(obj); !result.Status.IsAllow() {
+		t.Errorf("expected matching object, got: %v by rule %v", result.Status, result.RuleID)
 	}
 
 	obj = NewAzureObject(
@@ -148,8 +150,8 @@ test:
 			"principal.type":           "group",
 		},
 	)
-	if ruleId, status := config.Test.Validate(obj); !status.IsDeny() {
-		t.Errorf("expected NOT matching object, got: %v by rule %v", status, ruleId)
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() {
+		t.Errorf("expected NOT matching object, got: %v by rule %v", result.Status, result.RuleID)
 	}
 
 }
@@ -187,8 +189,8 @@ test:
 			"principal.type":           "group",
 		},
 	)
-	if ruleId, status := config.Test.Validate(obj); !status.IsAllow() {
-		t.Errorf("expected matching object, got: %v by rule %v", status, ruleId)
+	if result := config.Test.Validate(obj); !result.Status.IsAllow() {
+		t.Errorf("expected matching object, got: %v by rule %v", result.Status, result.RuleID)
 	}
 
 	obj = NewAzureObject(
@@ -199,8 +201,8 @@ test:
 			"principal.type":           "group",
 		},
 	)
-	if ruleId, status := config.Test.Validate(obj); !status.IsDeny() || ruleId != "deny" {
-		t.Errorf("expected NOT matching object with rule deny, got: %v by rule %v", status, ruleId)
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny" {
+		t.Errorf("expected NOT matching object with rule deny, got: %v by rule %v", result.Status, result.RuleID)
 	}
 
 }
@@ -229,8 +231,8 @@ test:
 			"resourcegroup.tag.updated": time.Now().Format("YYYY-MM-DD"),
 		},
 	)
-	if ruleId, status := config.Test.Validate(obj); !status.IsAllow() {
-		t.Errorf("expected matching object, got: %v by rule %v", status, ruleId)
+	if result := config.Test.Validate(obj); !result.Status.IsAllow() {
+		t.Errorf("expected matching object, got: %v by rule %v", result.Status, result.RuleID)
 	}
 
 	obj = NewAzureObject(
@@ -238,8 +240,448 @@ test:
 			"resourcegroup.tag.updated": "2000-01-01",
 		},
 	)
-	if ruleId, status := config.Test.Validate(obj); !status.IsDeny() || ruleId != "deny" {
-		t.Errorf("expected NOT matching object with rule deny, got: %v by rule %v", status, ruleId)
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny" {
+		t.Errorf("expected NOT matching object with rule deny, got: %v by rule %v", result.Status, result.RuleID)
 	}
 
 }
+
+func TestValidationNumeric(t *testing.T) {
+	var obj *AzureObject
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+      - rule: deny-retention
+        workspace.retentiondays: { min: 0, max: 29 }
+        action: deny
+      - rule: deny-capacity
+        sku.capacity: { notEqual: 2 }
+        action: deny
+      - rule: ignore-count
+        query.count: 0
+        action: ignore
+      - rule: deny-string
+        resource.tag.retention: { parseAs: number, max: 30 }
+        action: deny
+      - rule: allow
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		data           map[string]interface{}
+		expectedRuleId string
+	}{
+		{map[string]interface{}{"workspace.retentiondays": int64(7)}, "deny-retention"},
+		{map[string]interface{}{"workspace.retentiondays": int32(90)}, "allow"},
+		{map[string]interface{}{"workspace.retentiondays": float64(29.5)}, "allow"},
+		{map[string]interface{}{"workspace.retentiondays": json.Number("29")}, "deny-retention"},
+		{map[string]interface{}{"sku.capacity": 3}, "deny-capacity"},
+		{map[string]interface{}{"sku.capacity": 2}, "allow"},
+		{map[string]interface{}{"query.count": float64(0)}, "ignore-count"},
+		{map[string]interface{}{"query.count": int64(1)}, "allow"},
+		{map[string]interface{}{"resource.tag.retention": "30"}, "deny-string"},
+		{map[string]interface{}{"resource.tag.retention": "365"}, "allow"},
+		{map[string]interface{}{"resource.tag.retention": "forever"}, "allow"},
+	}
+
+	for _, testCase := range testCases {
+		obj = NewAzureObject(testCase.data)
+		if result := config.Test.Validate(obj); result.RuleID != testCase.expectedRuleId {
+			t.Errorf("expected rule %v for %v, got: %v", testCase.expectedRuleId, testCase.data, result.RuleID)
+		}
+	}
+}
+
+func TestValidationNumericString(t *testing.T) {
+	var obj *AzureObject
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+    - rule: deny-equal
+      resource.tag.replicas: 5
+      action: deny
+    - rule: deny-notequal
+      resource.tag.zones: { notEqual: 3 }
+      action: deny
+    - rule: deny-range
+      resource.tag.retention: { min: 1, max: 30 }
+      action: deny
+    - rule: allow
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// numeric conditions on string values without parseAs only match numeric strings
+	testCases := []struct {
+		data           map[string]interface{}
+		expectedRuleId string
+	}{
+		{map[string]interface{}{"resource.tag.replicas": "5"}, "deny-equal"},
+		{map[string]interface{}{"resource.tag.replicas": " 5.0 "}, "deny-equal"},
+		{map[string]interface{}{"resource.tag.replicas": "6"}, "allow"},
+		{map[string]interface{}{"resource.tag.replicas": "five"}, "allow"},
+		{map[string]interface{}{"resource.tag.zones": "2"}, "deny-notequal"},
+		{map[string]interface{}{"resource.tag.zones": "3"}, "allow"},
+		{map[string]interface{}{"resource.tag.zones": "zone-redundant"}, "allow"},
+		{map[string]interface{}{"resource.tag.retention": "7"}, "deny-range"},
+		{map[string]interface{}{"resource.tag.retention": "90"}, "allow"},
+		{map[string]interface{}{"resource.tag.retention": "forever"}, "allow"},
+	}
+
+	for _, testCase := range testCases {
+		obj = NewAzureObject(testCase.data)
+		if result := config.Test.Validate(obj); result.RuleID != testCase.expectedRuleId {
+			t.Errorf("expected rule %v for %v, got: %v", testCase.expectedRuleId, testCase.data, result.RuleID)
+		}
+	}
+}
+
+func TestValidationConditions(t *testing.T) {
+	var obj *AzureObject
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+      - rule: deny-owner
+        role.name: Owner
+        anyOf:
+          - principal.type: User
+          - scope.type: subscription
+        not:
+          principal.displayname: { anyOf: ["Break Glass"] }
+        action: deny
+      - rule: deny-nested
+        allOf:
+          - role.name: Contributor
+          - anyOf:
+              - principal.type: Guest
+              - not:
+                  scope.type: resourcegroup
+        action: deny
+      - rule: allow
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	testCases := []struct {
+		data           map[string]interface{}
+		expectedRuleId string
+	}{
+		{map[string]interface{}{"role.name": "Owner", "principal.type": "User", "scope.type": "resourcegroup", "principal.displayname": "John"}, "deny-owner"},
+		{map[string]interface{}{"role.name": "Owner", "principal.type": "Group", "scope.type": "subscription", "principal.displayname": "Admins"}, "deny-owner"},
+		{map[string]interface{}{"role.name": "Owner", "principal.type": "Group", "scope.type": "resourcegroup", "principal.displayname": "Admins"}, "allow"},
+		{map[string]interface{}{"role.name": "Owner", "principal.type": "User", "scope.type": "subscription", "principal.displayname": "Break Glass"}, "allow"},
+		{map[string]interface{}{"role.name": "Contributor", "principal.type": "Guest", "scope.type": "resourcegroup"}, "deny-nested"},
+		{map[string]interface{}{"role.name": "Contributor", "principal.type": "User", "scope.type": "subscription"}, "deny-nested"},
+		{map[string]interface{}{"role.name": "Contributor", "principal.type": "User", "scope.type": "resourcegroup"}, "allow"},
+		{map[string]interface{}{"role.name": "Reader", "principal.type": "Guest", "scope.type": "subscription"}, "allow"},
+	}
+
+	for _, testCase := range testCases {
+		obj = NewAzureObject(testCase.data)
+		if result := config.Test.Validate(obj); result.RuleID != testCase.expectedRuleId {
+			t.Errorf("expected rule %v for %v, got: %v", testCase.expectedRuleId, testCase.data, result.RuleID)
+		}
+	}
+
+	invalidYamlConfig := `
+test:
+  enabled: true
+  rules:
+      - rule: invalid
+        anyOf: foobar
+`
+	if err := yaml.Unmarshal([]byte(invalidYamlConfig), &TestValidator{}); err == nil {
+		t.Errorf("expected error for invalid anyOf condition")
+	}
+}
+
+func TestValidationFunc(t *testing.T) {
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+      - rule: deny-func
+        func: |-
+          var leaked = (typeof leaked === "undefined") ? 0 : leaked + 1;
+          obj["principal.type"] === "unknown" && leaked === 0
+        action: deny
+      - rule: allow
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// parallel evaluation, state must not leak between evaluations
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			obj := NewAzureObject(map[string]interface{}{"principal.type": "unknown"})
+			if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny-func" {
+				t.Errorf("expected NOT matching object with rule deny-func, got: %v by rule %v", result.Status, result.RuleID)
+			}
+
+			obj = NewAzureObject(map[string]interface{}{"principal.type": "user"})
+			if result := config.Test.Validate(obj); !result.Status.IsAllow() {
+				t.Errorf("expected matching object, got: %v by rule %v", result.Status, result.RuleID)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestValidationFuncIsolation(t *testing.T) {
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+      - rule: deny-modified-builtin
+        func: |-
+          var modified = (typeof Array.prototype.leaked !== "undefined") || JSON.stringify === undefined;
+          Array.prototype.leaked = true;
+          JSON.stringify = undefined;
+          modified
+        action: deny
+      - rule: deny-recursion
+        func: |-
+          function recurse(n) { return recurse(n + 1); }
+          obj["principal.type"] === "recursion" && recurse(0)
+        action: deny
+      - rule: allow
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	// modified builtins must not leak into the next evaluation
+	for i := 0; i < 5; i++ {
+		obj := NewAzureObject(map[string]interface{}{"principal.type": "user"})
+		if result := config.Test.Validate(obj); !result.Status.IsAllow() {
+			t.Errorf("expected matching object (isolated runtime), got: %v by rule %v", result.Status, result.RuleID)
+		}
+	}
+
+	// runaway recursion is stopped by the stack depth limit and fails closed
+	obj := NewAzureObject(map[string]interface{}{"principal.type": "recursion"})
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny-recursion" || result.Error == "" {
+		t.Errorf("expected NOT matching object with rule deny-recursion and error, got: %v by rule %v (error: %v)", result.Status, result.RuleID, result.Error)
+	}
+}
+
+func TestValidationFuncTimeout(t *testing.T) {
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+      - rule: deny-endless
+        func: |-
+          while (true) {}
+        action: deny
+      - rule: allow
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	defaultFuncTimeout := FuncTimeout
+	FuncTimeout = 100 * time.Millisecond
+	defer func() {
+		FuncTimeout = defaultFuncTimeout
+	}()
+
+	// runaway func must fail closed and not fall through to the allow rule
+	start := time.Now()
+	obj := NewAzureObject(map[string]interface{}{"principal.type": "unknown"})
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny-endless" || result.Error == "" {
+		t.Errorf("expected NOT matching object with rule deny-endless and error (func timeout), got: %v by rule %v (error: %v)", result.Status, result.RuleID, result.Error)
+	}
+
+	if duration := time.Since(start); duration > 5*time.Second {
+		t.Errorf("expected func to be stopped after timeout, took: %v", duration)
+	}
+}
+
+func TestValidationRuleMetadata(t *testing.T) {
+	yamlConfig := `
+
+test:
+  enabled: true
+  prometheus:
+    labels:
+      resourceID: resource.id
+  rules:
+      - rule: deny-public
+        description: public access is not allowed
+        severity: High
+        owner: team-network
+        remediation: https://example.com/remediation
+        references: [https://example.com/a, https://example.com/b]
+        storage.publicaccess: "true"
+        action: deny
+  scopeRules:
+    /scope:
+      - rule: deny-public
+        severity: low
+        storage.publicblob: "true"
+        action: deny
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	obj := NewAzureObject(map[string]interface{}{"resource.id": "/foo", "storage.publicaccess": "true"})
+	result := config.Test.Validate(obj)
+	if !result.Status.IsDeny() || result.RuleID != "deny-public" {
+		t.Errorf("expected NOT matching object with rule deny-public, got: %v by rule %v", result.Status, result.RuleID)
+	}
+
+	metadata := result.Metadata
+	if metadata.Severity != "high" || metadata.Owner != "team-network" || metadata.Description != "public access is not allowed" {
+		t.Errorf("unexpected rule metadata: %v", metadata)
+	}
+	if metadata.Remediation != "https://example.com/remediation" || len(metadata.References) != 2 {
+		t.Errorf("unexpected rule remediation/references: %v", metadata)
+	}
+
+	labels := config.Test.CreatePrometheusMetricFromAzureObject(obj, result)
+	if labels["severity"] != "high" || labels["rule"] != "deny-public" || labels["resourceID"] != "/foo" {
+		t.Errorf("unexpected prometheus labels: %v", labels)
+	}
+
+	// same rule name in scope rules, metadata must be the one of the matching rule
+	obj = NewAzureObject(map[string]interface{}{"resource.id": "/scope/foo", "storage.publicaccess": "false", "storage.publicblob": "true"})
+	result = config.Test.Validate(obj)
+	if result.RuleID != "deny-public" || result.Metadata.Severity != "low" {
+		t.Errorf("expected severity low of scope rule deny-public, got: %v by rule %v", result.Metadata.Severity, result.RuleID)
+	}
+
+	// default deny has no metadata
+	obj = NewAzureObject(map[string]interface{}{"resource.id": "/bar", "storage.publicaccess": "false"})
+	result = config.Test.Validate(obj)
+	if labels := config.Test.CreatePrometheusMetricFromAzureObject(obj, result); labels["severity"] != "" {
+		t.Errorf("expected empty severity for default deny, got: %v", labels)
+	}
+
+	invalidYamlConfig := `
+test:
+  enabled: true
+  rules:
+      - rule: invalid
+        severity: urgent
+`
+	if err := yaml.Unmarshal([]byte(invalidYamlConfig), &TestValidator{}); err == nil {
+		t.Errorf("expected error for invalid severity")
+	}
+}
+
+func TestValidationExemptions(t *testing.T) {
+	yamlConfig := `
+
+test:
+  enabled: true
+  rules:
+      - rule: deny-public
+        storage.publicaccess: "true"
+        action: deny
+      - rule: allow
+  exemptions:
+      - rule: deny-public
+        resourceID: /subscriptions/xxx/resourcegroups/website/providers/microsoft.storage/storageaccounts/static
+        reason: static website content
+        approvedBy: security-team
+        expires: "2999-01-01"
+      - rule: deny-public
+        resourcegroup.name: legacy
+        reason: migration in progress
+        approvedBy: security-team
+        expires: "2000-01-01"
+`
+
+	config := TestValidator{}
+	if err := yaml.Unmarshal([]byte(yamlConfig), &config); err != nil {
+		t.Error(err)
+		return
+	}
+
+	obj := NewAzureObject(map[string]interface{}{
+		"resource.id":          "/subscriptions/xxx/resourcegroups/website/providers/microsoft.storage/storageaccounts/static",
+		"resourcegroup.name":   "website",
+		"storage.publicaccess": "true",
+	})
+	if result := config.Test.Validate(obj); !result.Status.IsExempt() || result.RuleID != "deny-public" {
+		t.Errorf("expected exempt object with rule deny-public, got: %v by rule %v", result.Status, result.RuleID)
+	} else if result.Exemption == nil || result.Exemption.Reason != "static website content" || result.Exemption.ApprovedBy != "security-team" {
+		t.Errorf("expected exemption details in result, got: %v", result.Exemption)
+	}
+
+	// expired exemption
+	obj = NewAzureObject(map[string]interface{}{
+		"resource.id":          "/subscriptions/xxx/resourcegroups/legacy/providers/microsoft.storage/storageaccounts/legacy",
+		"resourcegroup.name":   "legacy",
+		"storage.publicaccess": "true",
+	})
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny-public" {
+		t.Errorf("expected NOT matching object with rule deny-public (expired exemption), got: %v by rule %v", result.Status, result.RuleID)
+	} else if result.Exemption != nil {
+		t.Errorf("expected no exemption details for expired exemption, got: %v", result.Exemption)
+	}
+
+	// not exempted
+	obj = NewAzureObject(map[string]interface{}{
+		"resource.id":          "/subscriptions/xxx/resourcegroups/website/providers/microsoft.storage/storageaccounts/other",
+		"resourcegroup.name":   "website",
+		"storage.publicaccess": "true",
+	})
+	if result := config.Test.Validate(obj); !result.Status.IsDeny() || result.RuleID != "deny-public" {
+		t.Errorf("expected NOT matching object with rule deny-public, got: %v by rule %v", result.Status, result.RuleID)
+	}
+
+	// missing mandatory fields
+	invalidYamlConfig := `
+test:
+  enabled: true
+  exemptions:
+      - rule: deny-public
+        resourceID: /subscriptions/xxx
+        reason: missing approval
+        expires: "2999-01-01"
+`
+	if err := yaml.Unmarshal([]byte(invalidYamlConfig), &TestValidator{}); err == nil {
+		t.Errorf("expected error for exemption without approvedBy")
+	}
+}
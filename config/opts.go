@@ -30,18 +30,54 @@ type (
 
 		// scrape times
 		Cronjobs struct {
-			KeyvaultAccessPolicies string `long:"cron.keytvaultaccesspolicies" env:"CRON_KEYTVAULTACCESSPOLICIES"  description:"Cronjob for KeyVault AccessPolicies report" default:"0 * * * *"`
-			ResourceGroups         string `long:"cron.resourcegroups"          env:"CRON_RESOURCEGROUPS"           description:"Cronjob for ResourceGroups report"          default:"*/30 * * * *"`
-			ResourceProvider       string `long:"cron.resourceproviders"       env:"CRON_RESOURCEPROVIDERS"        description:"Cronjob for ResourceProviders report"       default:"0 * * * *"`
-			RoleAssignments        string `long:"cron.roleassignments"         env:"CRON_ROLEASSIGNMENTS"          description:"Cronjob for RoleAssignments report"         default:"*/5 * * * *"`
-			ResourceGraph          string `long:"cron.resourcegraph"           env:"CRON_RESOURCEGRAPH"            description:"Cronjob for ResourceGraph report"           default:"15 * * * *"`
-			LogAnalytics           string `long:"cron.loganalytics"            env:"CRON_LOGANALYTICS"             description:"Cronjob for LogAnalytics report"            default:"30 * * * *"`
+			KeyvaultAccessPolicies          string `long:"cron.keytvaultaccesspolicies"         env:"CRON_KEYTVAULTACCESSPOLICIES"          description:"Cronjob for KeyVault AccessPolicies report"         default:"0 * * * *"`
+			NetworkSecurityGroups           string `long:"cron.networksecuritygroups"           env:"CRON_NETWORKSECURITYGROUPS"            description:"Cronjob for NetworkSecurityGroups report"           default:"0 * * * *"`
+			ResourceGroups                  string `long:"cron.resourcegroups"                  env:"CRON_RESOURCEGROUPS"                   description:"Cronjob for ResourceGroups report"                  default:"*/30 * * * *"`
+			ResourceProvider                string `long:"cron.resourceproviders"               env:"CRON_RESOURCEPROVIDERS"                description:"Cronjob for ResourceProviders report"               default:"0 * * * *"`
+			RoleAssignments                 string `long:"cron.roleassignments"                 env:"CRON_ROLEASSIGNMENTS"                  description:"Cronjob for RoleAssignments report"                 default:"*/5 * * * *"`
+			RoleAssignmentsManagementGroups string `long:"cron.roleassignments.managementgroup" env:"CRON_ROLEASSIGNMENTS_MANAGEMENTGROUP"  description:"Cronjob for ManagementGroup RoleAssignments report"`
+			StorageAccounts                 string `long:"cron.storageaccounts"                 env:"CRON_STORAGEACCOUNTS"                  description:"Cronjob for StorageAccounts report"                 default:"0 * * * *"`
+			DenyAssignments                 string `long:"cron.denyassignments"                 env:"CRON_DENYASSIGNMENTS"                  description:"Cronjob for DenyAssignments report"                 default:"0 * * * *"`
+			ClassicAdministrators           string `long:"cron.classicadministrators"           env:"CRON_CLASSICADMINISTRATORS"            description:"Cronjob for ClassicAdministrators report"           default:"0 * * * *"`
+			Applications                    string `long:"cron.applications"                    env:"CRON_APPLICATIONS"                     description:"Cronjob for Entra ID Applications report"           default:"0 * * * *"`
+			PolicyCompliance                string `long:"cron.policycompliance"                env:"CRON_POLICYCOMPLIANCE"                 description:"Cronjob for PolicyCompliance report"                default:"30 * * * *"`
+			DiagnosticSettings              string `long:"cron.diagnosticsettings"              env:"CRON_DIAGNOSTICSETTINGS"               description:"Cronjob for DiagnosticSettings report"              default:"0 */6 * * *"`
+			ResourceLocks                   string `long:"cron.resourcelocks"                   env:"CRON_RESOURCELOCKS"                    description:"Cronjob for ResourceLocks report"                   default:"*/30 * * * *"`
+			KeyvaultSettings                string `long:"cron.keyvaultsettings"                env:"CRON_KEYVAULTSETTINGS"                 description:"Cronjob for KeyVault settings report"               default:"0 * * * *"`
+			KeyvaultItems                   string `long:"cron.keyvaultitems"                   env:"CRON_KEYVAULTITEMS"                    description:"Cronjob for KeyVault items report"                  default:"0 * * * *"`
+			RoleDefinitions                 string `long:"cron.roledefinitions"                 env:"CRON_ROLEDEFINITIONS"                  description:"Cronjob for RoleDefinitions report"                 default:"0 * * * *"`
+			Resources                       string `long:"cron.resources"                       env:"CRON_RESOURCES"                        description:"Cronjob for Resources report"                       default:"0 * * * *"`
+			Subscriptions                   string `long:"cron.subscriptions"                   env:"CRON_SUBSCRIPTIONS"                    description:"Cronjob for Subscriptions report"                   default:"0 * * * *"`
+			DefenderPlans                   string `long:"cron.defenderplans"                   env:"CRON_DEFENDERPLANS"                    description:"Cronjob for Defender plans report"                  default:"0 * * * *"`
+			ConditionalAccess               string `long:"cron.conditionalaccess"               env:"CRON_CONDITIONALACCESS"                description:"Cronjob for ConditionalAccess report"               default:"0 * * * *"`
+			DirectoryRoles                  string `long:"cron.directoryroles"                  env:"CRON_DIRECTORYROLES"                   description:"Cronjob for DirectoryRoles report"                  default:"*/15 * * * *"`
+			FederatedCredentials            string `long:"cron.federatedcredentials"            env:"CRON_FEDERATEDCREDENTIALS"             description:"Cronjob for FederatedCredentials report"            default:"0 * * * *"`
+			PublicIPs                       string `long:"cron.publicips"                       env:"CRON_PUBLICIPS"                        description:"Cronjob for PublicIPs report"                       default:"0 * * * *"`
+			Databases                       string `long:"cron.databases"                       env:"CRON_DATABASES"                        description:"Cronjob for Databases report"                       default:"0 * * * *"`
+			ResourceGraph                   string `long:"cron.resourcegraph"                   env:"CRON_RESOURCEGRAPH"                    description:"Cronjob for ResourceGraph report"                   default:"15 * * * *"`
+			LogAnalytics                    string `long:"cron.loganalytics"                    env:"CRON_LOGANALYTICS"                     description:"Cronjob for LogAnalytics report"                    default:"30 * * * *"`
+		}
+
+		DiagnosticSettings struct {
+			Concurrency int `long:"diagnosticsettings.concurrency"  env:"DIAGNOSTICSETTINGS_CONCURRENCY"  description:"Number of parallel DiagnosticSettings requests per subscription" default:"5"`
 		}
 
 		LogAnalytics struct {
 			WaitTime time.Duration `long:"loganalytics.waitduration"           env:"LOGANALYTICS_WAITDURATION"     description:"Wait duration between LogAnalytics queries" default:"5s"`
 		}
 
+		ResourceLocks struct {
+			ProductionTags []string `long:"resourcelocks.production.tag"  env:"RESOURCELOCKS_PRODUCTION_TAG"  env-delim:" "  description:"ResourceGroup tags (name=value) of production ResourceGroups, missing locks are reported for these ResourceGroups" default:"environment=production"`
+		}
+
+		RoleAssignments struct {
+			Pim bool `long:"roleassignments.pim"  env:"ROLEASSIGNMENTS_PIM"  description:"Include Privileged Identity Management (PIM) eligible and active schedules in RoleAssignments reports"`
+		}
+
+		Rules struct {
+			FuncTimeout time.Duration `long:"rules.func.timeout"  env:"RULES_FUNC_TIMEOUT"  description:"Max execution time of javascript func rules per object (0 = no timeout)" default:"1s"`
+		}
+
 		Config []string `long:"config"   env:"CONFIG" env-delim:":"   description:"Config file path"      required:"true"`
 		DryRun bool     `long:"dry-run"  env:"DRYRUN"                 description:"Dry Run (report only)"`
 
@@ -226,6 +226,63 @@ func startHttpServer() {
 		case "KeyvaultAccessPolicy":
 			templatePayload.ReportConfig = templatePayload.Config.KeyvaultAccessPolicies
 			templatePayload.RequestReport = selectedReport
+		case "NetworkSecurityGroup":
+			templatePayload.ReportConfig = templatePayload.Config.NetworkSecurityGroups
+			templatePayload.RequestReport = selectedReport
+		case "StorageAccount":
+			templatePayload.ReportConfig = templatePayload.Config.StorageAccounts
+			templatePayload.RequestReport = selectedReport
+		case "DenyAssignment":
+			templatePayload.ReportConfig = templatePayload.Config.DenyAssignments
+			templatePayload.RequestReport = selectedReport
+		case "ClassicAdministrator":
+			templatePayload.ReportConfig = templatePayload.Config.ClassicAdministrators
+			templatePayload.RequestReport = selectedReport
+		case "Application":
+			templatePayload.ReportConfig = templatePayload.Config.Applications
+			templatePayload.RequestReport = selectedReport
+		case "PolicyCompliance":
+			templatePayload.ReportConfig = templatePayload.Config.PolicyCompliance
+			templatePayload.RequestReport = selectedReport
+		case "DiagnosticSetting":
+			templatePayload.ReportConfig = templatePayload.Config.DiagnosticSettings
+			templatePayload.RequestReport = selectedReport
+		case "ResourceLock":
+			templatePayload.ReportConfig = templatePayload.Config.ResourceLocks
+			templatePayload.RequestReport = selectedReport
+		case "KeyvaultSettings":
+			templatePayload.ReportConfig = templatePayload.Config.KeyvaultSettings
+			templatePayload.RequestReport = selectedReport
+		case "KeyvaultItem":
+			templatePayload.ReportConfig = templatePayload.Config.KeyvaultItems
+			templatePayload.RequestReport = selectedReport
+		case "RoleDefinition":
+			templatePayload.ReportConfig = templatePayload.Config.RoleDefinitions
+			templatePayload.RequestReport = selectedReport
+		case "Resource":
+			templatePayload.ReportConfig = templatePayload.Config.Resources
+			templatePayload.RequestReport = selectedReport
+		case "Subscription":
+			templatePayload.ReportConfig = templatePayload.Config.Subscriptions
+			templatePayload.RequestReport = selectedReport
+		case "DefenderPlan":
+			templatePayload.ReportConfig = templatePayload.Config.DefenderPlans
+			templatePayload.RequestReport = selectedReport
+		case "ConditionalAccess":
+			templatePayload.ReportConfig = templatePayload.Config.ConditionalAccess
+			templatePayload.RequestReport = selectedReport
+		case "DirectoryRole":
+			templatePayload.ReportConfig = templatePayload.Config.DirectoryRoles
+			templatePayload.RequestReport = selectedReport
+		case "FederatedCredential":
+			templatePayload.ReportConfig = templatePayload.Config.FederatedCredentials
+			templatePayload.RequestReport = selectedReport
+		case "PublicIP":
+			templatePayload.ReportConfig = templatePayload.Config.PublicIPs
+			templatePayload.RequestReport = selectedReport
+		case "Database":
+			templatePayload.ReportConfig = templatePayload.Config.Databases
+			templatePayload.RequestReport = selectedReport
 		case "ResourceGraph":
 			if len(reportInfo) == 2 && reportInfo[1] != "" {
 				if v, ok := templatePayload.Config.ResourceGraph.Queries[reportInfo[1]]; ok {
@@ -299,6 +356,10 @@ func startHttpServer() {
 							line.GroupBy = line.RuleID
 						case "status":
 							line.GroupBy = line.Status
+						case "severity":
+							line.GroupBy = line.Severity
+						case "owner":
+							line.GroupBy = line.Owner
 						default:
 							if val, ok := line.Resource[*reportGroupBy]; ok {
 								line.GroupBy = val
@@ -129,6 +129,44 @@ let yamlFormatter = (cell, formatterParams) => {
     return val;
 };
 
+let ruleFormatter = (cell, formatterParams) => {
+    let row = cell.getRow().getData();
+    let val = $("<div>").text(cell.getValue()).html();
+
+    if (row.description) {
+        val += "<br><small>" + $("<div>").text(row.description).html() + "</small>";
+    }
+
+    if (row.owner) {
+        val += "<br><small><b>owner:</b> " + $("<div>").text(row.owner).html() + "</small>";
+    }
+
+    if (row.error) {
+        val += "<br><small><b>error:</b> " + $("<div>").text(row.error).html() + "</small>";
+    }
+
+    if (row.exemptionReason) {
+        val += "<br><small><b>exemption:</b> " + $("<div>").text(row.exemptionReason).html()
+            + " (approved by " + $("<div>").text(row.exemptionApprovedBy).html()
+            + ", expires " + $("<div>").text(row.exemptionExpires).html() + ")</small>";
+    }
+
+    let links = [];
+    if (row.remediation) {
+        links.push(row.remediation);
+    }
+    if (row.references) {
+        links = links.concat(row.references);
+    }
+    links.forEach((link, num) => {
+        let title = (num === 0 && row.remediation) ? "remediation" : "reference";
+        let linkEl = $("<a>").attr("href", link).attr("target", "_blank").attr("rel", "noopener").text(title);
+        val += "<br><small>" + linkEl.prop("outerHTML") + "</small>";
+    });
+
+    return val;
+};
+
 let ajaxRequestFunc = (url, config, params) => {
     url = url + "?" + new URLSearchParams(params).toString();
     return new Promise(function (resolve, reject) {
@@ -191,7 +229,8 @@ let table = new Tabulator("#report-table", {
     columns: [
         {title:"Status", field:"status", formatter:"plaintext", width:100},
         {title:"Resource", field:"resource", formatter:yamlFormatter, formatterPrint:yamlFormatter},
-        {title:"Rule", field:"rule", formatter:"plaintext",  width:300},
+        {title:"Severity", field:"severity", formatter:"plaintext", width:100},
+        {title:"Rule", field:"rule", formatter:ruleFormatter, formatterPrint:ruleFormatter, width:300},
         {title:"Count", field:"count", formatter:"plaintext",  width:100},
     ],
 